@@ -24,15 +24,11 @@ export default function ScrapeForm() {
       });
 
       if (ok) {
-        showSuccess("URL scrapeada exitosamente");
+        showSuccess("Scraping en cola, el resultado aparecerá al terminar");
         setUrl("");
         setShowScheduleOption(true);
         setTimeout(() => setShowScheduleOption(false), 5000);
-
-        // Reload results if not in pagination mode
-        if (!usePagination) {
-          loadResults();
-        }
+        watchJob(data.data.id);
       } else {
         showError(data.error || "Error al scrapear la URL");
       }
@@ -43,6 +39,30 @@ export default function ScrapeForm() {
     }
   };
 
+  // Polls the queued job until it finishes and reports the outcome
+  const watchJob = async (jobId) => {
+    for (let i = 0; i < 120; i++) {
+      await new Promise((resolve) => setTimeout(resolve, 2000));
+      const { ok, data } = await apiRequest(`/jobs/${jobId}`);
+      if (!ok) return;
+
+      const job = data.data;
+      if (job.status === "completed") {
+        showSuccess("URL scrapeada exitosamente");
+        // Reload results if not in pagination mode
+        if (!usePagination) {
+          loadResults();
+        }
+        return;
+      }
+      if (job.status === "failed") {
+        showError(job.error || "Error al scrapear la URL");
+        return;
+      }
+      if (job.status === "cancelled") return;
+    }
+  };
+
   const handleCreateSchedule = () => {
     openScheduleModal(url.trim());
     setShowScheduleOption(false);
@@ -12,6 +12,11 @@ function getSEOConfig(score) {
   return SEO_SCORE_CONFIG.low;
 }
 
+// Solo <a> y <area> son enlaces navegables; srcset, style, iframes, etc. no.
+function isHyperlink(link) {
+  return typeof link === "string" || !link.source || link.source === "a" || link.source === "area";
+}
+
 function Badge({ children, color = "gray" }) {
   const colors = {
     teal:   "bg-teal-500/20 text-teal-400",
@@ -58,7 +63,7 @@ export default function DetailModal({ result, onClose }) {
   } = result;
 
   const safeHeaders   = headers        ?? [];
-  const safeLinks     = links          ?? [];
+  const safeLinks     = (links         ?? []).filter(isHyperlink);
   const safeImages    = images         ?? [];
   const safeContent   = content        ?? "";
   const safeSchemaOrg = schema_org     ?? [];
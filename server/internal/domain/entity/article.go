@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Article metadata sources, from the most to the least reliable.
+const (
+	ArticleSourceJSONLD    = "json_ld"
+	ArticleSourceMeta      = "meta"
+	ArticleSourceMicrodata = "microdata"
+	ArticleSourceTime      = "time"
+	ArticleSourceByline    = "byline"
+	ArticleSourceURL       = "url"
+)
+
+// Article holds the publication metadata of a page, each field taken from
+// the most reliable source that has it.
+type Article struct {
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	PublishedSource string     `json:"published_source,omitempty"`
+	ModifiedAt      *time.Time `json:"modified_at,omitempty"`
+	ModifiedSource  string     `json:"modified_source,omitempty"`
+	Authors         []string   `json:"authors"`
+	AuthorsSource   string     `json:"authors_source,omitempty"`
+}
+
+// Result sort orders.
+const (
+	ResultSortCreated   = "created_at"
+	ResultSortPublished = "published_at"
+)
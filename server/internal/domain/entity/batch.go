@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+// Batch states. Items reuse the job states.
+const (
+	BatchRunning   = "running"
+	BatchCompleted = "completed"
+)
+
+// Batch kinds. A redirect batch follows the URL of every item and checks
+// that it ends on the expected URL; nothing is saved.
+const (
+	BatchScrape    = "scrape"
+	BatchRedirects = "redirects"
+)
+
+// Batch is a bulk scrape of a list of URLs, or the verification of a
+// redirect map.
+type Batch struct {
+	ID      int64                  `json:"id"`
+	UserID  int64                  `json:"user_id"`
+	Name    string                 `json:"name"`
+	Kind    string                 `json:"kind"`
+	Status  string                 `json:"status"`
+	Options *ScrapeOptionsOverride `json:"options,omitempty"`
+	// Progress, counted from the items
+	Total      int        `json:"total"`
+	Succeeded  int        `json:"succeeded"`
+	Failed     int        `json:"failed"`
+	Pending    int        `json:"pending"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Duplicates and Rejected report the input cleanup; they are only
+	// returned when the batch is created
+	Duplicates int           `json:"duplicates,omitempty"`
+	Rejected   []RejectedURL `json:"rejected,omitempty"`
+}
+
+type RejectedURL struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// BatchItem is one URL of a batch. Items of a redirect batch have the
+// Expected URL and, once checked, the Redirect outcome.
+type BatchItem struct {
+	ID        int64          `json:"id"`
+	BatchID   int64          `json:"batch_id"`
+	URL       string         `json:"url"`
+	Expected  string         `json:"expected,omitempty"`
+	Status    string         `json:"status"`
+	ResultID  *int64         `json:"result_id,omitempty"`
+	Redirect  *RedirectCheck `json:"redirect,omitempty"`
+	ErrorCode string         `json:"error_code,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+type CreateBatchRequest struct {
+	Name    string                 `json:"name"`
+	URLs    []string               `json:"urls"`
+	Options *ScrapeOptionsOverride `json:"options,omitempty"`
+}
+
+// BatchSummary is the outcome of a batch.
+type BatchSummary struct {
+	BatchID         int64          `json:"batch_id"`
+	Status          string         `json:"status"`
+	Total           int            `json:"total"`
+	Succeeded       int            `json:"succeeded"`
+	Failed          int            `json:"failed"`
+	Pending         int            `json:"pending"`
+	FailuresByClass map[string]int `json:"failures_by_class"`
+	AverageSEOScore float64        `json:"average_seo_score"`
+	WorstPages      []BatchPage    `json:"worst_pages"`
+	// MixedContent is the number of pages with mixed content
+	MixedContent int `json:"mixed_content_pages"`
+}
+
+type BatchPage struct {
+	URL      string `json:"url"`
+	ResultID int64  `json:"result_id"`
+	Title    string `json:"title"`
+	SEOScore int    `json:"seo_score"`
+}
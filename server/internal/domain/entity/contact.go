@@ -0,0 +1,81 @@
+package entity
+
+// Contact source types: where on the page a contact was found.
+const (
+	ContactSourceMailto     = "mailto"
+	ContactSourceTel        = "tel"
+	ContactSourceText       = "text"
+	ContactSourceObfuscated = "obfuscated"
+	ContactSourceSchemaOrg  = "schema_org"
+	ContactSourceMicrodata  = "microdata"
+	ContactSourceLink       = "link"
+)
+
+// Page regions, from the nearest landmark element or role around a node.
+const (
+	RegionHeader     = "header"
+	RegionNavigation = "navigation"
+	RegionMain       = "main"
+	RegionAside      = "aside"
+	RegionFooter     = "footer"
+	RegionBody       = "body"
+)
+
+// ContactSource is one place a contact was found: the kind of markup and
+// the region of the page it sits in.
+type ContactSource struct {
+	Type   string `json:"type"`
+	Region string `json:"region,omitempty"`
+}
+
+// Contacts gathers the contact data of a page, each entry once with every
+// place it was found.
+type Contacts struct {
+	Emails         []ContactEmail  `json:"emails"`
+	Phones         []ContactPhone  `json:"phones"`
+	Addresses      []PostalAddress `json:"addresses"`
+	SocialProfiles []SocialProfile `json:"social_profiles"`
+}
+
+// ContactEmail is an email address, lower-cased.
+type ContactEmail struct {
+	Address string          `json:"address"`
+	Sources []ContactSource `json:"sources"`
+}
+
+// ContactPhone is a phone number as written on the page. E164 is set when
+// the country could be told, from the number itself or from the page.
+type ContactPhone struct {
+	Number  string          `json:"number"`
+	E164    string          `json:"e164,omitempty"`
+	Sources []ContactSource `json:"sources"`
+}
+
+// PostalAddress is a schema.org PostalAddress.
+type PostalAddress struct {
+	StreetAddress string          `json:"street_address,omitempty"`
+	Locality      string          `json:"locality,omitempty"`
+	Region        string          `json:"region,omitempty"`
+	PostalCode    string          `json:"postal_code,omitempty"`
+	Country       string          `json:"country,omitempty"`
+	PostOfficeBox string          `json:"post_office_box,omitempty"`
+	Sources       []ContactSource `json:"sources"`
+}
+
+// Social networks recognised in profile links.
+const (
+	SocialLinkedIn  = "linkedin"
+	SocialX         = "x"
+	SocialFacebook  = "facebook"
+	SocialInstagram = "instagram"
+	SocialYouTube   = "youtube"
+	SocialGitHub    = "github"
+)
+
+// SocialProfile is a link to a profile page, normalised to https without
+// query or trailing slash.
+type SocialProfile struct {
+	Network string          `json:"network"`
+	URL     string          `json:"url"`
+	Sources []ContactSource `json:"sources"`
+}
@@ -0,0 +1,119 @@
+package entity
+
+import "strings"
+
+// RedactedValue replaces secrets in API responses. Sending it back on an
+// update keeps the stored value.
+const RedactedValue = "********"
+
+// RequestCredentials are the custom headers, cookies and HTTP authentication
+// sent with a scrape. They are encrypted at rest and redacted in responses.
+type RequestCredentials struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies []RequestCookie   `json:"cookies,omitempty"`
+	Auth    *HTTPAuth         `json:"auth,omitempty"`
+}
+
+type RequestCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HTTPAuth struct {
+	Type     string `json:"type"` // basic | bearer
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+func (c *RequestCredentials) IsEmpty() bool {
+	return c == nil || (len(c.Headers) == 0 && len(c.Cookies) == 0 && c.Auth == nil)
+}
+
+// Redacted returns a copy safe to show: cookie values, passwords, tokens and
+// sensitive-looking header values are masked.
+func (c *RequestCredentials) Redacted() *RequestCredentials {
+	if c == nil {
+		return nil
+	}
+	out := &RequestCredentials{}
+	if len(c.Headers) > 0 {
+		out.Headers = make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			if IsSensitiveHeader(name) {
+				value = RedactedValue
+			}
+			out.Headers[name] = value
+		}
+	}
+	for _, cookie := range c.Cookies {
+		out.Cookies = append(out.Cookies, RequestCookie{Name: cookie.Name, Value: RedactedValue})
+	}
+	if c.Auth != nil {
+		auth := *c.Auth
+		if auth.Password != "" {
+			auth.Password = RedactedValue
+		}
+		if auth.Token != "" {
+			auth.Token = RedactedValue
+		}
+		out.Auth = &auth
+	}
+	return out
+}
+
+// MergeRedacted returns c with every RedactedValue replaced by the matching
+// value from previous, so clients can send back what they were shown.
+func (c *RequestCredentials) MergeRedacted(previous *RequestCredentials) *RequestCredentials {
+	if c == nil || previous == nil {
+		return c
+	}
+	out := &RequestCredentials{Auth: c.Auth}
+	if len(c.Headers) > 0 {
+		out.Headers = make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			if value == RedactedValue {
+				for prevName, prevValue := range previous.Headers {
+					if strings.EqualFold(prevName, name) {
+						value = prevValue
+						break
+					}
+				}
+			}
+			out.Headers[name] = value
+		}
+	}
+	for _, cookie := range c.Cookies {
+		if cookie.Value == RedactedValue {
+			for _, prev := range previous.Cookies {
+				if prev.Name == cookie.Name {
+					cookie.Value = prev.Value
+					break
+				}
+			}
+		}
+		out.Cookies = append(out.Cookies, cookie)
+	}
+	if c.Auth != nil && previous.Auth != nil {
+		auth := *c.Auth
+		if auth.Password == RedactedValue {
+			auth.Password = previous.Auth.Password
+		}
+		if auth.Token == RedactedValue {
+			auth.Token = previous.Auth.Token
+		}
+		out.Auth = &auth
+	}
+	return out
+}
+
+// IsSensitiveHeader reports whether a header usually carries a secret.
+func IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, marker := range []string{"authorization", "cookie", "token", "secret", "key", "auth", "session", "password"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package entity
+
+// PageFingerprint is the part of a result used to compare pages.
+type PageFingerprint struct {
+	ResultID    int64
+	URL         string
+	FinalURL    string
+	Title       string
+	Description string
+	H1          string
+	WordCount   int
+	SimHash     string
+}
+
+// DuplicateReport groups the results of a user that repeat each other.
+type DuplicateReport struct {
+	Threshold float64 `json:"threshold"`
+	// Clusters are pages whose main text is at least Threshold similar
+	Clusters     []DuplicateCluster `json:"clusters"`
+	Titles       []ExactDuplicate   `json:"titles"`
+	Descriptions []ExactDuplicate   `json:"descriptions"`
+	H1s          []ExactDuplicate   `json:"h1s"`
+	// ThinPages have fewer words than the thin page limit
+	ThinPages []DuplicatePage `json:"thin_pages"`
+}
+
+type DuplicateCluster struct {
+	// Similarity is the lowest similarity between linked pages of the cluster
+	Similarity float64         `json:"similarity"`
+	Pages      []DuplicatePage `json:"pages"`
+}
+
+// ExactDuplicate is a title, description or H1 shared by several pages.
+type ExactDuplicate struct {
+	Value string          `json:"value"`
+	Pages []DuplicatePage `json:"pages"`
+}
+
+type DuplicatePage struct {
+	ResultID  int64  `json:"result_id"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	WordCount int    `json:"word_count"`
+}
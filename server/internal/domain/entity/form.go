@@ -0,0 +1,41 @@
+package entity
+
+// Form security issue codes.
+const (
+	// FormPasswordOverHTTP is a password field on a page served over HTTP
+	FormPasswordOverHTTP = "password_over_http"
+	// FormInsecureAction is a form of an HTTPS page that posts to HTTP
+	FormInsecureAction = "insecure_action"
+	// FormCrossOrigin is a form that submits to another origin
+	FormCrossOrigin = "cross_origin_action"
+	// FormPasswordInGET is a GET form with a password field, which puts
+	// the password in the URL
+	FormPasswordInGET = "password_in_get"
+)
+
+// Form is a form of the page. Action is resolved against the page and
+// Method is upper-cased, GET when missing.
+type Form struct {
+	ID           string      `json:"id,omitempty"`
+	Name         string      `json:"name,omitempty"`
+	Action       string      `json:"action"`
+	Method       string      `json:"method"`
+	Enctype      string      `json:"enctype,omitempty"`
+	Autocomplete string      `json:"autocomplete,omitempty"`
+	Region       string      `json:"region"`
+	Fields       []FormField `json:"fields"`
+	// CSRFField names the hidden field that looks like an anti-CSRF token
+	CSRFField string `json:"csrf_field,omitempty"`
+	// Captcha is the captcha widget found: recaptcha, hcaptcha, turnstile
+	// or friendly_captcha
+	Captcha string   `json:"captcha,omitempty"`
+	Issues  []string `json:"issues"`
+}
+
+// FormField is an input, select or textarea of a form.
+type FormField struct {
+	Name         string `json:"name,omitempty"`
+	Type         string `json:"type"`
+	Required     bool   `json:"required"`
+	Autocomplete string `json:"autocomplete,omitempty"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+// LinkGraph is the internal link graph of a site, built from the latest
+// result of every page scraped on the host.
+type LinkGraph struct {
+	Host string `json:"host"`
+	// Root is the home page the click depth is measured from; empty when the
+	// home page has not been scraped
+	Root     string      `json:"root"`
+	Nodes    []GraphNode `json:"nodes"`
+	Edges    []GraphEdge `json:"edges"`
+	Orphans  []string    `json:"orphans"`
+	DeadEnds []string    `json:"dead_ends"`
+}
+
+// GraphNode is a scraped page of the site.
+type GraphNode struct {
+	URL        string `json:"url"`
+	ResultID   int64  `json:"result_id"`
+	Title      string `json:"title"`
+	StatusCode int    `json:"status_code"`
+	// Inlinks counts the scraped pages linking here; Outlinks the internal
+	// links of the page, scraped or not
+	Inlinks  int `json:"inlinks"`
+	Outlinks int `json:"outlinks"`
+	// Depth is the number of clicks from the home page, -1 if unreachable
+	Depth    int     `json:"depth"`
+	PageRank float64 `json:"pagerank"`
+	Orphan   bool    `json:"orphan"`
+	DeadEnd  bool    `json:"dead_end"`
+}
+
+// GraphEdge is a link between two scraped pages.
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Anchor string `json:"anchor"`
+}
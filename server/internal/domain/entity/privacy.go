@@ -0,0 +1,36 @@
+package entity
+
+// ThirdPartyScript groups the scripts a page loads from a third-party
+// host. Category and Company are set when the host is a known tracker.
+type ThirdPartyScript struct {
+	Host     string `json:"host"`
+	Scripts  int    `json:"scripts"`
+	Category string `json:"category,omitempty"`
+	Company  string `json:"company,omitempty"`
+}
+
+// Cookie is a cookie set by the first load of a page, before any consent.
+// The value is not kept. Lifetime is in seconds and 0 for session cookies;
+// a negative lifetime deletes the cookie.
+type Cookie struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain,omitempty"`
+	// Host is the host that set a host-only cookie, one without Domain
+	Host     string `json:"host,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Session  bool   `json:"session"`
+	Lifetime int64  `json:"lifetime_seconds"`
+	Secure   bool   `json:"secure"`
+	HTTPOnly bool   `json:"http_only"`
+	SameSite string `json:"same_site,omitempty"`
+}
+
+// PrivacyReport is the privacy audit of a result, as exported.
+type PrivacyReport struct {
+	ResultID          int64              `json:"result_id"`
+	URL               string             `json:"url"`
+	FinalURL          string             `json:"final_url"`
+	ThirdPartyScripts []ThirdPartyScript `json:"third_party_scripts"`
+	Cookies           []Cookie           `json:"cookies"`
+	ConsentPlatform   string             `json:"consent_platform,omitempty"`
+}
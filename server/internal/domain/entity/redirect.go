@@ -0,0 +1,88 @@
+package entity
+
+// Redirect issue codes.
+const (
+	RedirectLoop                 = "redirect_loop"
+	RedirectLongChain            = "long_chain"
+	RedirectSchemeFlipFlop       = "scheme_flip_flop"
+	RedirectHTTPSDowngrade       = "https_downgrade"
+	RedirectTemporaryAsPermanent = "temporary_as_permanent"
+)
+
+// Redirect types. HTTP hops are 3xx responses; the client-side ones come
+// from the page itself.
+const (
+	RedirectHTTP        = "http"
+	RedirectMetaRefresh = "meta_refresh"
+	RedirectJavaScript  = "javascript"
+)
+
+// RedirectHop is one redirect response of a fetch.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	// Type is RedirectHTTP, or the client-side redirect that was followed
+	Type string `json:"type"`
+	// Location is the absolute URL the hop redirects to
+	Location  string `json:"location"`
+	LatencyMs int64  `json:"latency_ms"`
+	// CrossHost and CrossScheme report whether Location changes the host or
+	// the protocol of URL
+	CrossHost   bool `json:"cross_host"`
+	CrossScheme bool `json:"cross_scheme"`
+	// SetCookie holds the raw Set-Cookie headers of the hop for the privacy
+	// audit; they carry cookie values and are not stored
+	SetCookie []string `json:"-"`
+}
+
+// RedirectIssue is a problem found in the redirect chain. Hop is the index
+// of the hop it refers to, -1 for the chain as a whole.
+type RedirectIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hop     int    `json:"hop"`
+}
+
+// ClientRedirect is a meta refresh or JavaScript redirect found in a page.
+// Delay is in seconds; Followed reports whether the scrape went on to
+// Target.
+type ClientRedirect struct {
+	Type     string `json:"type"`
+	Target   string `json:"target"`
+	Delay    int    `json:"delay"`
+	Followed bool   `json:"followed"`
+}
+
+// RedirectMapping is one old→new row of a redirect map.
+type RedirectMapping struct {
+	Source   string `json:"source"`
+	Expected string `json:"expected"`
+}
+
+// FailureRedirectMismatch is the error code of the items of a redirect
+// batch that do not pass.
+const FailureRedirectMismatch = "redirect_mismatch"
+
+// RedirectCheck is the outcome of following one mapping.
+type RedirectCheck struct {
+	Source   string `json:"source"`
+	Expected string `json:"expected"`
+	FinalURL string `json:"final_url"`
+	Hops     int    `json:"hops"`
+	// StatusCodes has the status of every hop and of the final response
+	StatusCodes []int  `json:"status_codes"`
+	Pass        bool   `json:"pass"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// RedirectReport is the outcome of a redirect batch. Rows only has the
+// mappings checked so far.
+type RedirectReport struct {
+	BatchID int64           `json:"batch_id"`
+	Status  string          `json:"status"`
+	Total   int             `json:"total"`
+	Passed  int             `json:"passed"`
+	Failed  int             `json:"failed"`
+	Pending int             `json:"pending"`
+	Rows    []RedirectCheck `json:"rows"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+// Resource types.
+const (
+	ResourceScript     = "script"
+	ResourceStylesheet = "stylesheet"
+	ResourceFont       = "font"
+	ResourceImage      = "image"
+	ResourceMedia      = "media"
+	ResourceIframe     = "iframe"
+	ResourceOther      = "other"
+)
+
+// Resource is a sub-resource loaded by a page. Hint is the preload or
+// prefetch hint that names it, if any. The transfer fields are only filled
+// in by the page weight probe.
+type Resource struct {
+	Type           string `json:"type"`
+	URL            string `json:"url"`
+	ThirdParty     bool   `json:"third_party"`
+	Async          bool   `json:"async"`
+	Defer          bool   `json:"defer"`
+	RenderBlocking bool   `json:"render_blocking"`
+	Hint           string `json:"hint,omitempty"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	TransferBytes  int64  `json:"transfer_bytes,omitempty"`
+	// Encoding is the Content-Encoding of the response, "" when uncompressed
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// PageWeight adds up the transfer sizes of the page and its resources.
+type PageWeight struct {
+	TotalBytes int64            `json:"total_bytes"`
+	HTMLBytes  int64            `json:"html_bytes"`
+	ByType     map[string]int64 `json:"by_type"`
+	Probed     int              `json:"probed"`
+	Failed     int              `json:"failed"`
+	// Uncompressed counts the text resources (scripts, stylesheets) served
+	// without compression
+	Uncompressed int        `json:"uncompressed"`
+	Largest      []Resource `json:"largest"`
+}
@@ -3,28 +3,37 @@ package entity
 import "time"
 
 type Schedule struct {
-	ID        int64      `json:"id"`
-	UserID    int64      `json:"user_id"`
-	Name      string     `json:"name"`
-	URL       string     `json:"url"`
-	CronExpr  string     `json:"cron_expression"`
-	Active    bool       `json:"active"`
-	LastRun   *time.Time `json:"last_run,omitempty"`
-	NextRun   *time.Time `json:"next_run,omitempty"`
-	RunCount  int        `json:"run_count"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID       int64                  `json:"id"`
+	UserID   int64                  `json:"user_id"`
+	Name     string                 `json:"name"`
+	URL      string                 `json:"url"`
+	CronExpr string                 `json:"cron_expression"`
+	Active   bool                   `json:"active"`
+	LastRun  *time.Time             `json:"last_run,omitempty"`
+	NextRun  *time.Time             `json:"next_run,omitempty"`
+	RunCount int                    `json:"run_count"`
+	Options  *ScrapeOptionsOverride `json:"options,omitempty"`
+	// Credentials are stored encrypted and only returned redacted
+	Credentials *RequestCredentials `json:"credentials,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
 }
 
 type CreateScheduleRequest struct {
-	Name     string `json:"name" validate:"required,min=3,max=100"`
-	URL      string `json:"url" validate:"required,url"`
-	CronExpr string `json:"cron_expression" validate:"required"`
+	Name        string                 `json:"name" validate:"required,min=3,max=100"`
+	URL         string                 `json:"url" validate:"required,url"`
+	CronExpr    string                 `json:"cron_expression" validate:"required"`
+	Options     *ScrapeOptionsOverride `json:"options,omitempty"`
+	Credentials *RequestCredentials    `json:"credentials,omitempty"`
 }
 
 type UpdateScheduleRequest struct {
-	Name     *string `json:"name,omitempty"`
-	URL      *string `json:"url,omitempty"`
-	CronExpr *string `json:"cron_expression,omitempty"`
-	Active   *bool   `json:"active,omitempty"`
+	Name     *string                `json:"name,omitempty"`
+	URL      *string                `json:"url,omitempty"`
+	CronExpr *string                `json:"cron_expression,omitempty"`
+	Active   *bool                  `json:"active,omitempty"`
+	Options  *ScrapeOptionsOverride `json:"options,omitempty"`
+	// Credentials replaces the stored credentials; redacted values keep the
+	// previous secret and an empty object removes them
+	Credentials *RequestCredentials `json:"credentials,omitempty"`
 }
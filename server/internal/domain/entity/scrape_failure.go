@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// Failure codes of a scrape attempt. They are stable and safe to filter on.
+const (
+	FailureDNS               = "dns_error"
+	FailureTLS               = "tls_error"
+	FailureTimeout           = "timeout"
+	FailureConnectionRefused = "connection_refused"
+	FailureConnectionReset   = "connection_reset"
+	FailureTooManyRedirects  = "too_many_redirects"
+	FailureBlocked           = "blocked_destination"
+	FailureRateLimited       = "rate_limited"
+	FailureHTTP4xx           = "http_4xx"
+	FailureHTTP5xx           = "http_5xx"
+	FailureNetwork           = "network_error"
+	// FailureInternal covers errors after the fetch, e.g. saving the result
+	FailureInternal = "internal_error"
+)
+
+// ScrapeFailure is a failed attempt of a scrape. Retried attempts are
+// recorded too, with Retried set.
+type ScrapeFailure struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	Code       string    `json:"code"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Retried    bool      `json:"retried"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PaginatedScrapeFailures struct {
+	Data       []*ScrapeFailure    `json:"data"`
+	Pagination *PaginationResponse `json:"pagination"`
+}
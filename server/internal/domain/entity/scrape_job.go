@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Scrape job states. A job starts pending, is picked up by a worker and
+// ends completed, failed or cancelled.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// ScrapeJob is a scrape submitted to the queue.
+type ScrapeJob struct {
+	ID      int64                  `json:"id"`
+	UserID  int64                  `json:"user_id"`
+	URL     string                 `json:"url"`
+	Options *ScrapeOptionsOverride `json:"options,omitempty"`
+	// Credentials are stored encrypted and never returned
+	Credentials *RequestCredentials `json:"-"`
+	Status      string              `json:"status"`
+	ResultID    *int64              `json:"result_id,omitempty"`
+	Error       string              `json:"error,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	FinishedAt  *time.Time          `json:"finished_at,omitempty"`
+	// QueueMs is the time spent waiting for a worker and DurationMs the
+	// time spent scraping
+	QueueMs    int64 `json:"queue_ms,omitempty"`
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// Finished reports whether the job reached a final state.
+func (j *ScrapeJob) Finished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed || j.Status == JobCancelled
+}
+
+// Request rebuilds the scrape request the job was submitted with.
+func (j *ScrapeJob) Request() *ScrapeRequest {
+	return &ScrapeRequest{URL: j.URL, Options: j.Options, Credentials: j.Credentials}
+}
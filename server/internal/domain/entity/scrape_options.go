@@ -0,0 +1,122 @@
+package entity
+
+// ScrapeOptions are the effective settings of a single scrape: which
+// extractors run and the limits applied to the fetch. Defaults come from the
+// scraping section of config.yaml.
+type ScrapeOptions struct {
+	ExtractLinks    bool   `json:"extract_links"`
+	ExtractImages   bool   `json:"extract_images"`
+	ExtractHeaders  bool   `json:"extract_headers"`
+	ExtractFavicon  bool   `json:"extract_favicon"`
+	FollowRedirects bool   `json:"follow_redirects"`
+	MaxRedirects    int    `json:"max_redirects"`
+	MaxLinks        int    `json:"max_links"`
+	MaxImages       int    `json:"max_images"`
+	Timeout         int    `json:"timeout"`
+	UserAgent       string `json:"user_agent"`
+	MaxBodyBytes    int    `json:"max_body_bytes"`
+	// MaxAttempts includes the first attempt; 1 disables retries.
+	MaxAttempts int `json:"max_attempts"`
+	// Proxy names the proxy pool to use; "direct" bypasses the default pool.
+	Proxy string `json:"proxy,omitempty"`
+	// Extractors enables or disables registered extractors by name. It takes
+	// precedence over the Extract* flags.
+	Extractors map[string]bool `json:"extractors,omitempty"`
+	// SessionProfileID logs in with the given session profile before the
+	// scrape; 0 scrapes anonymously.
+	SessionProfileID int64 `json:"session_profile_id,omitempty"`
+	// FollowClientRedirects follows meta refresh and JavaScript redirects as
+	// extra redirect hops; by default they are only reported.
+	FollowClientRedirects bool `json:"follow_client_redirects,omitempty"`
+}
+
+// ScrapeOptionsOverride carries the overrides sent with a scrape request or
+// stored on a schedule. Nil fields keep the default value.
+type ScrapeOptionsOverride struct {
+	ExtractLinks          *bool           `json:"extract_links,omitempty"`
+	ExtractImages         *bool           `json:"extract_images,omitempty"`
+	ExtractHeaders        *bool           `json:"extract_headers,omitempty"`
+	ExtractFavicon        *bool           `json:"extract_favicon,omitempty"`
+	FollowRedirects       *bool           `json:"follow_redirects,omitempty"`
+	MaxRedirects          *int            `json:"max_redirects,omitempty"`
+	MaxLinks              *int            `json:"max_links,omitempty"`
+	MaxImages             *int            `json:"max_images,omitempty"`
+	Timeout               *int            `json:"timeout,omitempty"`
+	UserAgent             *string         `json:"user_agent,omitempty"`
+	MaxBodyBytes          *int            `json:"max_body_bytes,omitempty"`
+	MaxAttempts           *int            `json:"max_attempts,omitempty"`
+	Proxy                 *string         `json:"proxy,omitempty"`
+	Extractors            map[string]bool `json:"extractors,omitempty"`
+	SessionProfileID      *int64          `json:"session_profile_id,omitempty"`
+	FollowClientRedirects *bool           `json:"follow_client_redirects,omitempty"`
+}
+
+// Apply returns a copy of o with every non-nil field of ov applied on top.
+func (o ScrapeOptions) Apply(ov *ScrapeOptionsOverride) ScrapeOptions {
+	if ov == nil {
+		return o
+	}
+	if ov.ExtractLinks != nil {
+		o.ExtractLinks = *ov.ExtractLinks
+	}
+	if ov.ExtractImages != nil {
+		o.ExtractImages = *ov.ExtractImages
+	}
+	if ov.ExtractHeaders != nil {
+		o.ExtractHeaders = *ov.ExtractHeaders
+	}
+	if ov.ExtractFavicon != nil {
+		o.ExtractFavicon = *ov.ExtractFavicon
+	}
+	if ov.FollowRedirects != nil {
+		o.FollowRedirects = *ov.FollowRedirects
+	}
+	if ov.MaxRedirects != nil {
+		o.MaxRedirects = *ov.MaxRedirects
+	}
+	if ov.MaxLinks != nil {
+		o.MaxLinks = *ov.MaxLinks
+	}
+	if ov.MaxImages != nil {
+		o.MaxImages = *ov.MaxImages
+	}
+	if ov.Timeout != nil {
+		o.Timeout = *ov.Timeout
+	}
+	if ov.UserAgent != nil && *ov.UserAgent != "" {
+		o.UserAgent = *ov.UserAgent
+	}
+	if ov.MaxBodyBytes != nil {
+		o.MaxBodyBytes = *ov.MaxBodyBytes
+	}
+	if ov.MaxAttempts != nil {
+		o.MaxAttempts = *ov.MaxAttempts
+	}
+	if ov.FollowClientRedirects != nil {
+		o.FollowClientRedirects = *ov.FollowClientRedirects
+	}
+	if ov.Proxy != nil {
+		o.Proxy = *ov.Proxy
+	}
+	if len(ov.Extractors) > 0 {
+		merged := make(map[string]bool, len(o.Extractors)+len(ov.Extractors))
+		for name, enabled := range o.Extractors {
+			merged[name] = enabled
+		}
+		for name, enabled := range ov.Extractors {
+			merged[name] = enabled
+		}
+		o.Extractors = merged
+	}
+	if ov.SessionProfileID != nil {
+		o.SessionProfileID = *ov.SessionProfileID
+	}
+	return o
+}
+
+// ScrapeRequest is a single scrape as submitted through the API.
+type ScrapeRequest struct {
+	URL         string                 `json:"url"`
+	Options     *ScrapeOptionsOverride `json:"options,omitempty"`
+	Credentials *RequestCredentials    `json:"credentials,omitempty"`
+}
@@ -1,12 +1,43 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Link struct {
 	URL        string `json:"url"`
 	AnchorText string `json:"anchor_text"`
 	Rel        string `json:"rel"`
 	IsInternal bool   `json:"is_internal"`
+
+	// Source is the element or header the link comes from; links stored
+	// before it was recorded are all anchors
+	Source    string `json:"source,omitempty"`
+	NoFollow  bool   `json:"nofollow"`
+	Sponsored bool   `json:"sponsored"`
+	UGC       bool   `json:"ugc"`
+	// Region is the region of the page the element sits in, unset for
+	// <link> elements and the Link header
+	Region string `json:"region,omitempty"`
+}
+
+// Link sources.
+const (
+	LinkSourceAnchor = "a"
+	LinkSourceArea   = "area"
+	LinkSourceLink   = "link"
+	LinkSourceIframe = "iframe"
+	LinkSourceForm   = "form"
+	LinkSourceSrcset = "srcset"
+	LinkSourceStyle  = "style"
+	LinkSourceHeader = "header"
+)
+
+// IsHyperlink tells the links a visitor can follow, <a> and <area>, from
+// the URLs the page merely references.
+func (l Link) IsHyperlink() bool {
+	return l.Source == "" || l.Source == LinkSourceAnchor || l.Source == LinkSourceArea
 }
 
 type Image struct {
@@ -15,6 +46,32 @@ type Image struct {
 	Title string `json:"title"`
 }
 
+// Icon is a site icon declared by the page (link tags), by its web app manifest
+// or found at the conventional /favicon.ico location.
+type Icon struct {
+	URL     string `json:"url"`
+	Rel     string `json:"rel"`
+	Sizes   string `json:"sizes,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+	Color   string `json:"color,omitempty"`
+	Source  string `json:"source"`
+}
+
+type WebManifest struct {
+	URL             string `json:"url"`
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	Description     string `json:"description"`
+	ThemeColor      string `json:"theme_color"`
+	BackgroundColor string `json:"background_color"`
+	Display         string `json:"display"`
+	StartURL        string `json:"start_url"`
+	Icons           []Icon `json:"icons"`
+}
+
 type OGData struct {
 	Title       string `json:"title"`
 	URL         string `json:"url"`
@@ -34,40 +91,111 @@ type TwitterCard struct {
 }
 
 type ScrapingResult struct {
-	ID              int64       `json:"id"`
-	UserID          int64       `json:"user_id"`
-	URL             string      `json:"url"`
-	Title           string      `json:"title"`
-	Description     string      `json:"description"`
-	Keywords        string      `json:"keywords"`
-	Author          string      `json:"author"`
-	Language        string      `json:"language"`
-	Favicon         string      `json:"favicon"`
-	ImageURL        string      `json:"image_url"`
-	SiteName        string      `json:"site_name"`
-	Links           []Link      `json:"links"`
-	Images          []Image     `json:"images"`
-	Headers         []Header    `json:"headers"`
-	StatusCode      int         `json:"status_code"`
-	ContentType     string      `json:"content_type"`
-	WordCount       int         `json:"word_count"`
-	LoadTime        int64       `json:"load_time_ms"`
-	CanonicalURL    string      `json:"canonical_url"`
-	RobotsDirective string      `json:"robots_directive"`
-	XRobotsTag      string      `json:"x_robots_tag"`
-	Viewport        string      `json:"viewport"`
-	OGData          OGData      `json:"og_data"`
-	TwitterCard     TwitterCard `json:"twitter_card"`
-	SchemaOrg       []string    `json:"schema_org"`
-	RedirectChain   []string    `json:"redirect_chain"`
-	FinalURL        string      `json:"final_url"`
-	H1Count         int         `json:"h1_count"`
-	HasMultipleH1   bool        `json:"has_multiple_h1"`
-	SEOScore        int         `json:"seo_score"`
-	CreatedAt       time.Time   `json:"created_at"`
+	ID              int64                      `json:"id"`
+	UserID          int64                      `json:"user_id"`
+	URL             string                     `json:"url"`
+	Title           string                     `json:"title"`
+	Description     string                     `json:"description"`
+	Keywords        string                     `json:"keywords"`
+	Author          string                     `json:"author"`
+	Language        string                     `json:"language"`
+	Favicon         string                     `json:"favicon"`
+	Icons           []Icon                     `json:"icons"`
+	Manifest        *WebManifest               `json:"manifest,omitempty"`
+	ImageURL        string                     `json:"image_url"`
+	SiteName        string                     `json:"site_name"`
+	Links           []Link                     `json:"links"`
+	Images          []Image                    `json:"images"`
+	Headers         []Header                   `json:"headers"`
+	StatusCode      int                        `json:"status_code"`
+	ContentType     string                     `json:"content_type"`
+	BodyBytes       int64                      `json:"body_bytes"`
+	Truncated       bool                       `json:"truncated"`
+	WordCount       int                        `json:"word_count"`
+	SimHash         string                     `json:"simhash,omitempty"`
+	LoadTime        int64                      `json:"load_time_ms"`
+	CanonicalURL    string                     `json:"canonical_url"`
+	RobotsDirective string                     `json:"robots_directive"`
+	XRobotsTag      string                     `json:"x_robots_tag"`
+	Viewport        string                     `json:"viewport"`
+	OGData          OGData                     `json:"og_data"`
+	TwitterCard     TwitterCard                `json:"twitter_card"`
+	SchemaOrg       []string                   `json:"schema_org"`
+	RedirectChain   []string                   `json:"redirect_chain"`
+	RedirectHops    []RedirectHop              `json:"redirect_hops"`
+	RedirectIssues  []RedirectIssue            `json:"redirect_issues"`
+	ClientRedirects []ClientRedirect           `json:"client_redirects"`
+	FinalURL        string                     `json:"final_url"`
+	Proxy           string                     `json:"proxy,omitempty"`
+	H1Count         int                        `json:"h1_count"`
+	HasMultipleH1   bool                       `json:"has_multiple_h1"`
+	SEOScore        int                        `json:"seo_score"`
+	SEOIssues       []SEOIssue                 `json:"seo_issues"`
+	Tables          []Table                    `json:"tables"`
+	Extensions      map[string]json.RawMessage `json:"extensions,omitempty"`
+	CreatedAt       time.Time                  `json:"created_at"`
+
+	// MixedContent lists the plain HTTP sub-resources of an HTTPS page;
+	// MixedContentCount counts them all, even past the stored limit
+	MixedContent      []MixedContent `json:"mixed_content"`
+	MixedContentCount int            `json:"mixed_content_count"`
+
+	Resources []Resource `json:"resources"`
+	// PageWeight is only set when the page_weight extractor runs
+	PageWeight   *PageWeight  `json:"page_weight,omitempty"`
+	Technologies []Technology `json:"technologies"`
+
+	// ThirdPartyScripts, Cookies and ConsentPlatform make up the privacy
+	// audit of the first load of the page
+	ThirdPartyScripts []ThirdPartyScript `json:"third_party_scripts"`
+	Cookies           []Cookie           `json:"cookies"`
+	ConsentPlatform   string             `json:"consent_platform,omitempty"`
+
+	Contacts Contacts `json:"contacts"`
+	Article  Article  `json:"article"`
+	Forms    []Form   `json:"forms"`
+
+	// InternalLinks holds every <a> and <area> link to the site, past the
+	// max_links cap of Links, for the link graph
+	InternalLinks []Link `json:"-"`
+}
+
+// Mixed content types. Active content (scripts, stylesheets, frames, form
+// targets) is blocked by browsers; passive content (images and media) is
+// loaded with a warning.
+const (
+	MixedContentActive  = "active"
+	MixedContentPassive = "passive"
+)
+
+// MixedContent is a sub-resource of an HTTPS page referenced over HTTP.
+type MixedContent struct {
+	URL       string `json:"url"`
+	Element   string `json:"element"`
+	Attribute string `json:"attribute"`
+	Type      string `json:"type"`
 }
 
+// SEOIssue is a problem of the page that hurts its ranking.
+type SEOIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SEO issue codes.
+const (
+	SEOClientSideRedirect = "client_side_redirect"
+)
+
 type Header struct {
 	Level int    `json:"level"`
 	Text  string `json:"text"`
 }
+
+// Table is a data table of the page. Columns keeps the column order; each
+// row maps column names to cell text.
+type Table struct {
+	Caption string              `json:"caption,omitempty"`
+	Columns []string            `json:"columns"`
+	Rows    []map[string]string `json:"rows"`
+}
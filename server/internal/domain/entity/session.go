@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+// SessionProfile describes how to log in to a site through its login form.
+// The resulting cookies are reused by scrapes and schedules that reference
+// the profile.
+type SessionProfile struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	LoginURL string `json:"login_url"`
+	// FormAction overrides the action of the login form found at LoginURL.
+	FormAction    string              `json:"form_action,omitempty"`
+	UsernameField string              `json:"username_field"`
+	PasswordField string              `json:"password_field"`
+	ExtraFields   map[string]string   `json:"extra_fields,omitempty"`
+	Username      string              `json:"username"`
+	Password      string              `json:"password"`
+	SuccessCheck  SessionSuccessCheck `json:"success_check"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// SessionSuccessCheck decides whether a login worked. Every non-empty field
+// must match; when all are empty the login succeeds if the response is not
+// an error and no longer shows a password field.
+type SessionSuccessCheck struct {
+	CookieName   string `json:"cookie_name,omitempty"`
+	URLContains  string `json:"url_contains,omitempty"`
+	TextContains string `json:"text_contains,omitempty"`
+}
+
+// Redacted returns a copy of the profile with the password and the values
+// of the extra fields masked.
+func (p *SessionProfile) Redacted() *SessionProfile {
+	if p == nil {
+		return nil
+	}
+	redacted := *p
+	if redacted.Password != "" {
+		redacted.Password = RedactedValue
+	}
+	if len(p.ExtraFields) > 0 {
+		redacted.ExtraFields = make(map[string]string, len(p.ExtraFields))
+		for name := range p.ExtraFields {
+			redacted.ExtraFields[name] = RedactedValue
+		}
+	}
+	return &redacted
+}
+
+type CreateSessionProfileRequest struct {
+	Name          string              `json:"name"`
+	LoginURL      string              `json:"login_url"`
+	FormAction    string              `json:"form_action,omitempty"`
+	UsernameField string              `json:"username_field,omitempty"`
+	PasswordField string              `json:"password_field,omitempty"`
+	ExtraFields   map[string]string   `json:"extra_fields,omitempty"`
+	Username      string              `json:"username"`
+	Password      string              `json:"password"`
+	SuccessCheck  SessionSuccessCheck `json:"success_check"`
+}
+
+type UpdateSessionProfileRequest struct {
+	Name          *string              `json:"name,omitempty"`
+	LoginURL      *string              `json:"login_url,omitempty"`
+	FormAction    *string              `json:"form_action,omitempty"`
+	UsernameField *string              `json:"username_field,omitempty"`
+	PasswordField *string              `json:"password_field,omitempty"`
+	ExtraFields   map[string]string    `json:"extra_fields,omitempty"`
+	Username      *string              `json:"username,omitempty"`
+	Password      *string              `json:"password,omitempty"`
+	SuccessCheck  *SessionSuccessCheck `json:"success_check,omitempty"`
+}
+
+// SessionTestResult is the outcome of a fresh login with a profile.
+type SessionTestResult struct {
+	Success    bool       `json:"success"`
+	StatusCode int        `json:"status_code"`
+	FinalURL   string     `json:"final_url"`
+	Cookies    []string   `json:"cookies"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
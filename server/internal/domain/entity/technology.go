@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Technology is a product detected on a page by the fingerprint rules.
+// Confidence goes from 1 to 100.
+type Technology struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	Version    string   `json:"version,omitempty"`
+	Confidence int      `json:"confidence"`
+	Website    string   `json:"website,omitempty"`
+}
+
+// TechnologyUsage is a result on which a technology was detected.
+type TechnologyUsage struct {
+	ResultID   int64     `json:"result_id"`
+	URL        string    `json:"url"`
+	FinalURL   string    `json:"final_url"`
+	Title      string    `json:"title"`
+	Version    string    `json:"version,omitempty"`
+	Confidence int       `json:"confidence"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "webscraper-v2/internal/domain/entity"
+
+type BatchRepository interface {
+	// Create stores the batch together with its items, all pending.
+	Create(batch *entity.Batch, items []*entity.BatchItem) error
+	FindByID(id int64) (*entity.Batch, error)
+	FindByUserID(userID int64) ([]*entity.Batch, error)
+	// FindRunning returns the batches interrupted by a shutdown.
+	FindRunning() ([]*entity.Batch, error)
+	// FindItems lists the items of a batch; an empty status matches all.
+	FindItems(batchID int64, status string) ([]*entity.BatchItem, error)
+	UpdateItem(item *entity.BatchItem) error
+	// ResetRunningItems puts items left running back to pending.
+	ResetRunningItems(batchID int64) error
+	Finish(batchID int64) error
+	Summary(batchID int64, worst int) (*entity.BatchSummary, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "webscraper-v2/internal/domain/entity"
+
+type ScrapeFailureRepository interface {
+	Save(failure *entity.ScrapeFailure) error
+	// FindByUserIDPaginated lists failures newest first; empty code and url
+	// match everything.
+	FindByUserIDPaginated(userID int64, code, url string, pagination *entity.PaginationRequest) ([]*entity.ScrapeFailure, int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "webscraper-v2/internal/domain/entity"
+
+type ScrapeJobRepository interface {
+	Create(job *entity.ScrapeJob) error
+	FindByID(id int64) (*entity.ScrapeJob, error)
+	// ClaimNext marks the oldest pending job as running and returns it, or
+	// nil when the queue is empty.
+	ClaimNext() (*entity.ScrapeJob, error)
+	// Finish stores the final state of a job.
+	Finish(job *entity.ScrapeJob) error
+	// CancelPending cancels a job that no worker has picked up yet and
+	// reports whether it did.
+	CancelPending(id int64) (bool, error)
+	// Requeue moves running jobs back to pending, e.g. after a restart.
+	Requeue() (int64, error)
+}
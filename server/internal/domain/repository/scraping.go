@@ -9,6 +9,8 @@ type ScrapingRepository interface {
 	FindByID(id int64) (*entity.ScrapingResult, error)
 	Delete(id int64) error
 
-	FindAllByUserIDPaginated(userID int64, pagination *entity.PaginationRequest) ([]*entity.ScrapingResult, int64, error)
+	FindAllByUserIDPaginated(userID int64, sort string, pagination *entity.PaginationRequest) ([]*entity.ScrapingResult, int64, error)
 	CountByUserID(userID int64) (int64, error)
+	FindFingerprints(userID int64) ([]*entity.PageFingerprint, error)
+	FindByTechnology(userID int64, name string) ([]*entity.TechnologyUsage, error)
 }
@@ -0,0 +1,11 @@
+package repository
+
+import "webscraper-v2/internal/domain/entity"
+
+type SessionProfileRepository interface {
+	Create(profile *entity.SessionProfile) error
+	FindByID(id int64) (*entity.SessionProfile, error)
+	FindByUserID(userID int64) ([]*entity.SessionProfile, error)
+	Update(profile *entity.SessionProfile) error
+	Delete(id int64) error
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"webscraper-v2/pkg/crypto"
 
 	"gopkg.in/yaml.v3"
@@ -14,6 +16,7 @@ type Config struct {
 	Scraping ScrapingConfig `yaml:"scraping"`
 	Features FeaturesConfig `yaml:"features"`
 	Auth     AuthConfig     `yaml:"auth"`
+	Security SecurityConfig `yaml:"security"`
 	Chat     *ChatConfig    `yaml:"chat,omitempty"`
 }
 
@@ -34,6 +37,57 @@ type ScrapingConfig struct {
 	ExtractHeaders bool   `yaml:"extract_headers"`
 	MaxLinks       int    `yaml:"max_links"`
 	MaxImages      int    `yaml:"max_images"`
+	// MaxBodyBytes caps how much of a response body is read; longer pages
+	// are parsed up to the limit and marked as truncated.
+	MaxBodyBytes int         `yaml:"max_body_bytes"`
+	Proxy        ProxyConfig `yaml:"proxy"`
+	Retry        RetryConfig `yaml:"retry"`
+	Jobs         JobsConfig  `yaml:"jobs"`
+	Batch        BatchConfig `yaml:"batch"`
+
+	// LongRedirectChain is the most redirect hops a chain can have before
+	// it is flagged as too long. MaxRedirects still stops the fetch.
+	LongRedirectChain int `yaml:"long_redirect_chain"`
+	// FingerprintsFile is the technology fingerprint rule file, in the
+	// Wappalyzer format.
+	FingerprintsFile string `yaml:"fingerprints_file"`
+}
+
+// BatchConfig limits bulk scrapes. At most Concurrency scrapes run at a
+// time, across all batches.
+type BatchConfig struct {
+	Concurrency int `yaml:"concurrency"`
+	MaxURLs     int `yaml:"max_urls"`
+}
+
+// JobsConfig sizes the worker pool that processes queued scrapes.
+type JobsConfig struct {
+	Workers int `yaml:"workers"`
+	// PollInterval is how often idle workers look for pending jobs, in
+	// seconds; new submissions wake them immediately.
+	PollInterval int `yaml:"poll_interval"`
+}
+
+// RetryConfig controls retries of transient fetch failures (timeouts,
+// connection resets, 429 and 5xx) with exponential backoff and jitter.
+type RetryConfig struct {
+	MaxAttempts      int `yaml:"max_attempts"`
+	InitialBackoffMs int `yaml:"initial_backoff_ms"`
+	MaxBackoffMs     int `yaml:"max_backoff_ms"`
+}
+
+// ProxyConfig declares named pools of outbound proxies. Scrapes use
+// DefaultPool unless the request or schedule picks another one.
+type ProxyConfig struct {
+	DefaultPool string                     `yaml:"default_pool"`
+	Pools       map[string]ProxyPoolConfig `yaml:"pools"`
+}
+
+type ProxyPoolConfig struct {
+	Strategy    string   `yaml:"strategy"` // round_robin | random
+	URLs        []string `yaml:"urls"`
+	MaxFailures int      `yaml:"max_failures"`
+	Cooldown    int      `yaml:"cooldown"` // seconds
 }
 
 type FeaturesConfig struct {
@@ -48,6 +102,19 @@ type AuthConfig struct {
 	BCryptCost    int    `yaml:"bcrypt_cost"`
 	RequireAuth   bool   `yaml:"require_auth"`
 	DefaultRole   string `yaml:"default_role"`
+	// EncryptionKey protects credentials stored in the database. Falls back
+	// to the ENCRYPTION_KEY env var; required outside development.
+	EncryptionKey string `yaml:"encryption_key"`
+}
+
+// SecurityConfig restricts where server-side fetches may connect. Private,
+// loopback and link-local ranges are always blocked unless allowlisted.
+type SecurityConfig struct {
+	BlockedCIDRs []string `yaml:"blocked_cidrs"`
+	// AllowedCIDRs and AllowedHosts open blocked ranges for intranet audits.
+	// Hosts may use a "*.example.internal" wildcard.
+	AllowedCIDRs []string `yaml:"allowed_cidrs"`
+	AllowedHosts []string `yaml:"allowed_hosts"`
 }
 
 type ChatConfig struct {
@@ -71,6 +138,9 @@ func Load(path string) (*Config, error) {
 	if err := config.setupJWTSecret(); err != nil {
 		return nil, err
 	}
+	if err := config.setupEncryptionKey(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
@@ -92,12 +162,54 @@ func (c *Config) setDefaults() {
 	if c.Scraping.MaxRedirects == 0 {
 		c.Scraping.MaxRedirects = 10
 	}
+	if c.Scraping.LongRedirectChain == 0 {
+		c.Scraping.LongRedirectChain = 3
+	}
+	if c.Scraping.FingerprintsFile == "" {
+		c.Scraping.FingerprintsFile = "./fingerprints.json"
+	}
 	if c.Scraping.MaxLinks == 0 {
 		c.Scraping.MaxLinks = 100
 	}
 	if c.Scraping.MaxImages == 0 {
 		c.Scraping.MaxImages = 50
 	}
+	if c.Scraping.MaxBodyBytes == 0 {
+		c.Scraping.MaxBodyBytes = 10 << 20
+	}
+	if c.Scraping.Retry.MaxAttempts == 0 {
+		c.Scraping.Retry.MaxAttempts = 3
+	}
+	if c.Scraping.Retry.InitialBackoffMs == 0 {
+		c.Scraping.Retry.InitialBackoffMs = 500
+	}
+	if c.Scraping.Retry.MaxBackoffMs == 0 {
+		c.Scraping.Retry.MaxBackoffMs = 10000
+	}
+	if c.Scraping.Jobs.Workers == 0 {
+		c.Scraping.Jobs.Workers = 4
+	}
+	if c.Scraping.Jobs.PollInterval == 0 {
+		c.Scraping.Jobs.PollInterval = 5
+	}
+	if c.Scraping.Batch.Concurrency == 0 {
+		c.Scraping.Batch.Concurrency = 4
+	}
+	if c.Scraping.Batch.MaxURLs == 0 {
+		c.Scraping.Batch.MaxURLs = 1000
+	}
+	for name, pool := range c.Scraping.Proxy.Pools {
+		if pool.Strategy == "" {
+			pool.Strategy = "round_robin"
+		}
+		if pool.MaxFailures == 0 {
+			pool.MaxFailures = 3
+		}
+		if pool.Cooldown == 0 {
+			pool.Cooldown = 60
+		}
+		c.Scraping.Proxy.Pools[name] = pool
+	}
 	if c.Features.CacheDuration == 0 {
 		c.Features.CacheDuration = 3600
 	}
@@ -137,3 +249,36 @@ func (c *Config) setupJWTSecret() error {
 
 	return nil
 }
+
+func (c *Config) setupEncryptionKey() error {
+	if c.Auth.EncryptionKey == "" {
+		c.Auth.EncryptionKey = os.Getenv("ENCRYPTION_KEY")
+	}
+	if c.Auth.EncryptionKey != "" {
+		return nil
+	}
+	if os.Getenv("ENV") != "development" {
+		return fmt.Errorf("ENCRYPTION_KEY must be set in config.yaml or as environment variable in production")
+	}
+
+	// the development JWT secret changes on every start, so the key is
+	// generated once and kept next to the database
+	keyPath := filepath.Join(filepath.Dir(c.Database.Path), "encryption.key")
+	if data, err := os.ReadFile(keyPath); err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		c.Auth.EncryptionKey = strings.TrimSpace(string(data))
+		return nil
+	}
+	key, err := crypto.GenerateRandomSecret(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(keyPath), err)
+	}
+	if err := os.WriteFile(keyPath, []byte(key+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to save encryption key: %w", err)
+	}
+	c.Auth.EncryptionKey = key
+	fmt.Printf("⚠️  WARNING: Using an auto-generated encryption key saved in %s. Set ENCRYPTION_KEY env var or add encryption_key to config.yaml for production!\n", keyPath)
+	return nil
+}
@@ -24,7 +24,9 @@ func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
 		return nil, fmt.Errorf("no se pudo crear la carpeta de la DB: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", abs)
+	// scrape workers write concurrently: wait for the lock instead of
+	// failing with SQLITE_BUSY
+	db, err := sql.Open("sqlite", abs+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +141,91 @@ func (db *SQLiteDB) createTables() error {
 	if _, err := db.Exec(schedulesTriggerQuery); err != nil {
 		return err
 	}
+	sessionProfilesQuery := `
+	CREATE TABLE IF NOT EXISTS session_profiles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		login_url TEXT NOT NULL,
+		form_action TEXT DEFAULT '',
+		username_field TEXT NOT NULL,
+		password_field TEXT NOT NULL,
+		extra_fields TEXT DEFAULT '',
+		success_check TEXT DEFAULT '',
+		secrets TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_profiles_user_id ON session_profiles(user_id);`
+
+	if _, err := db.Exec(sessionProfilesQuery); err != nil {
+		return err
+	}
+	scrapeFailuresQuery := `
+	CREATE TABLE IF NOT EXISTS scrape_failures (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		attempt INTEGER NOT NULL DEFAULT 1,
+		code TEXT NOT NULL,
+		message TEXT DEFAULT '',
+		status_code INTEGER DEFAULT 0,
+		retried BOOLEAN NOT NULL DEFAULT false,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_scrape_failures_user_id ON scrape_failures(user_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_scrape_failures_code ON scrape_failures(code);`
+
+	if _, err := db.Exec(scrapeFailuresQuery); err != nil {
+		return err
+	}
+	scrapeJobsQuery := `
+	CREATE TABLE IF NOT EXISTS scrape_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		options TEXT DEFAULT '',
+		credentials TEXT DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending',
+		result_id INTEGER,
+		error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		started_at DATETIME,
+		finished_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_scrape_jobs_status ON scrape_jobs(status, id);
+	CREATE INDEX IF NOT EXISTS idx_scrape_jobs_user_id ON scrape_jobs(user_id);`
+
+	if _, err := db.Exec(scrapeJobsQuery); err != nil {
+		return err
+	}
+	batchesQuery := `
+	CREATE TABLE IF NOT EXISTS batches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'running',
+		options TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS batch_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		batch_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		result_id INTEGER,
+		error_code TEXT DEFAULT '',
+		error TEXT DEFAULT '',
+		FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_batches_user_id ON batches(user_id);
+	CREATE INDEX IF NOT EXISTS idx_batch_items_batch_id ON batch_items(batch_id, status);`
+
+	if _, err := db.Exec(batchesQuery); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -157,6 +244,36 @@ func (db *SQLiteDB) migrateV2() error {
 		`ALTER TABLE scraping_results ADD COLUMN h1_count INTEGER DEFAULT 0`,
 		`ALTER TABLE scraping_results ADD COLUMN has_multiple_h1 BOOLEAN DEFAULT false`,
 		`ALTER TABLE scraping_results ADD COLUMN seo_score INTEGER DEFAULT 0`,
+		`ALTER TABLE scraping_results ADD COLUMN icons TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN manifest TEXT DEFAULT ''`,
+		`ALTER TABLE schedules ADD COLUMN options TEXT DEFAULT ''`,
+		`ALTER TABLE scraping_results ADD COLUMN extensions TEXT DEFAULT ''`,
+		`ALTER TABLE scraping_results ADD COLUMN proxy TEXT DEFAULT ''`,
+		`ALTER TABLE schedules ADD COLUMN credentials TEXT DEFAULT ''`,
+		`ALTER TABLE scraping_results ADD COLUMN body_bytes INTEGER DEFAULT 0`,
+		`ALTER TABLE scraping_results ADD COLUMN truncated BOOLEAN DEFAULT false`,
+		`ALTER TABLE scraping_results ADD COLUMN tables TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN simhash TEXT DEFAULT ''`,
+		`ALTER TABLE scraping_results ADD COLUMN redirect_hops TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN redirect_issues TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN client_redirects TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN seo_issues TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN mixed_content TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN mixed_content_count INTEGER DEFAULT 0`,
+		`ALTER TABLE scraping_results ADD COLUMN resources TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN page_weight TEXT`,
+		`ALTER TABLE scraping_results ADD COLUMN technologies TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN third_party_scripts TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN cookies TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN consent_platform TEXT DEFAULT ''`,
+		`ALTER TABLE scraping_results ADD COLUMN contacts TEXT DEFAULT '{}'`,
+		`ALTER TABLE scraping_results ADD COLUMN article TEXT DEFAULT '{}'`,
+		`ALTER TABLE scraping_results ADD COLUMN published_at TEXT`,
+		`ALTER TABLE scraping_results ADD COLUMN forms TEXT DEFAULT '[]'`,
+		`ALTER TABLE scraping_results ADD COLUMN internal_links TEXT`,
+		`ALTER TABLE batches ADD COLUMN kind TEXT DEFAULT 'scrape'`,
+		`ALTER TABLE batch_items ADD COLUMN expected TEXT DEFAULT ''`,
+		`ALTER TABLE batch_items ADD COLUMN redirect_check TEXT DEFAULT ''`,
 	}
 	for _, stmt := range alterations {
 		if _, err := db.Exec(stmt); err != nil {
@@ -165,6 +282,16 @@ func (db *SQLiteDB) migrateV2() error {
 			}
 		}
 	}
+
+	// indexes on migrated columns can only be created once they exist
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_scraping_results_simhash ON scraping_results(user_id, simhash)`,
+		`CREATE INDEX IF NOT EXISTS idx_scraping_results_published_at ON scraping_results(user_id, published_at)`,
+	}
+	for _, stmt := range indexes {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("migration error: %w", err)
+		}
+	}
 	return nil
 }
-
@@ -0,0 +1,303 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/database"
+	"webscraper-v2/pkg/datetime"
+)
+
+const (
+	queryBatchCreate     = `INSERT INTO batches (user_id, name, kind, status, options, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	queryBatchItemCreate = `INSERT INTO batch_items (batch_id, url, expected, status) VALUES (?, ?, ?, 'pending')`
+	// progress is counted from the items on every read
+	queryBatchSelect = `SELECT b.id, b.user_id, b.name, b.kind, b.status, b.options, b.created_at, b.finished_at,
+			  (SELECT COUNT(*) FROM batch_items i WHERE i.batch_id = b.id),
+			  (SELECT COUNT(*) FROM batch_items i WHERE i.batch_id = b.id AND i.status = 'completed'),
+			  (SELECT COUNT(*) FROM batch_items i WHERE i.batch_id = b.id AND i.status = 'failed')
+			  FROM batches b`
+	queryBatchFindByID     = queryBatchSelect + ` WHERE b.id = ?`
+	queryBatchFindByUserID = queryBatchSelect + ` WHERE b.user_id = ? ORDER BY b.id DESC`
+	queryBatchFindRunning  = queryBatchSelect + ` WHERE b.status = 'running' ORDER BY b.id`
+	queryBatchFinish       = `UPDATE batches SET status = 'completed', finished_at = ? WHERE id = ?`
+	queryBatchItems        = `SELECT id, batch_id, url, expected, status, result_id, redirect_check, error_code, error
+			  FROM batch_items WHERE batch_id = ? AND (? = '' OR status = ?) ORDER BY id`
+	queryBatchItemUpdate = `UPDATE batch_items SET status = ?, result_id = ?, redirect_check = ?, error_code = ?, error = ?
+			  WHERE id = ?`
+	queryBatchItemReset  = `UPDATE batch_items SET status = 'pending' WHERE batch_id = ? AND status = 'running'`
+	queryBatchFailures   = `SELECT error_code, COUNT(*) FROM batch_items
+			  WHERE batch_id = ? AND status = 'failed' GROUP BY error_code`
+	queryBatchAvgSEO = `SELECT COALESCE(AVG(r.seo_score), 0) FROM batch_items i
+			  JOIN scraping_results r ON r.id = i.result_id WHERE i.batch_id = ? AND i.status = 'completed'`
+	queryBatchMixedContent = `SELECT COUNT(*) FROM batch_items i
+			  JOIN scraping_results r ON r.id = i.result_id
+			  WHERE i.batch_id = ? AND i.status = 'completed' AND r.mixed_content_count > 0`
+	queryBatchWorst = `SELECT i.url, r.id, COALESCE(r.title, ''), COALESCE(r.seo_score, 0) FROM batch_items i
+			  JOIN scraping_results r ON r.id = i.result_id WHERE i.batch_id = ? AND i.status = 'completed'
+			  ORDER BY r.seo_score ASC, i.id ASC LIMIT ?`
+)
+
+type batchRepository struct {
+	db *database.SQLiteDB
+}
+
+func NewBatchRepository(db *database.SQLiteDB) repository.BatchRepository {
+	return &batchRepository{db: db}
+}
+
+func (r *batchRepository) Create(batch *entity.Batch, items []*entity.BatchItem) error {
+	batch.CreatedAt = time.Now()
+	batch.Status = entity.BatchRunning
+
+	options := ""
+	if batch.Options != nil {
+		data, err := json.Marshal(batch.Options)
+		if err != nil {
+			return fmt.Errorf("error marshaling options: %w", err)
+		}
+		options = string(data)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(queryBatchCreate, batch.UserID, batch.Name, batch.Kind, batch.Status, options, batch.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating batch: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	stmt, err := tx.Prepare(queryBatchItemCreate)
+	if err != nil {
+		return fmt.Errorf("error preparing batch items: %w", err)
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		res, err := stmt.Exec(id, item.URL, item.Expected)
+		if err != nil {
+			return fmt.Errorf("error creating batch item: %w", err)
+		}
+		if item.ID, err = res.LastInsertId(); err != nil {
+			return fmt.Errorf("error getting last insert id: %w", err)
+		}
+		item.BatchID = id
+		item.Status = entity.JobPending
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing batch: %w", err)
+	}
+	batch.ID = id
+	batch.Total = len(items)
+	batch.Pending = len(items)
+	return nil
+}
+
+func (r *batchRepository) FindByID(id int64) (*entity.Batch, error) {
+	batch, err := r.scanBatch(r.db.QueryRow(queryBatchFindByID, id).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding batch by id: %w", err)
+	}
+	return batch, nil
+}
+
+func (r *batchRepository) FindByUserID(userID int64) ([]*entity.Batch, error) {
+	return r.queryBatches(queryBatchFindByUserID, userID)
+}
+
+func (r *batchRepository) FindRunning() ([]*entity.Batch, error) {
+	return r.queryBatches(queryBatchFindRunning)
+}
+
+func (r *batchRepository) queryBatches(query string, args ...interface{}) ([]*entity.Batch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying batches: %w", err)
+	}
+	defer rows.Close()
+
+	batches := []*entity.Batch{}
+	for rows.Next() {
+		batch, err := r.scanBatch(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return batches, nil
+}
+
+func (r *batchRepository) FindItems(batchID int64, status string) ([]*entity.BatchItem, error) {
+	rows, err := r.db.Query(queryBatchItems, batchID, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("error querying batch items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*entity.BatchItem{}
+	for rows.Next() {
+		item := &entity.BatchItem{}
+		var resultID sql.NullInt64
+		var expected, redirect, errorCode, errorMsg sql.NullString
+		if err := rows.Scan(&item.ID, &item.BatchID, &item.URL, &expected, &item.Status,
+			&resultID, &redirect, &errorCode, &errorMsg); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if resultID.Valid {
+			item.ResultID = &resultID.Int64
+		}
+		if redirect.String != "" {
+			var check entity.RedirectCheck
+			if err := json.Unmarshal([]byte(redirect.String), &check); err == nil {
+				item.Redirect = &check
+			}
+		}
+		item.Expected = expected.String
+		item.ErrorCode = errorCode.String
+		item.Error = errorMsg.String
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return items, nil
+}
+
+func (r *batchRepository) UpdateItem(item *entity.BatchItem) error {
+	redirect := ""
+	if item.Redirect != nil {
+		data, err := json.Marshal(item.Redirect)
+		if err != nil {
+			return fmt.Errorf("error marshaling redirect check: %w", err)
+		}
+		redirect = string(data)
+	}
+	if _, err := r.db.Exec(queryBatchItemUpdate, item.Status, item.ResultID, redirect, item.ErrorCode, item.Error, item.ID); err != nil {
+		return fmt.Errorf("error updating batch item: %w", err)
+	}
+	return nil
+}
+
+func (r *batchRepository) ResetRunningItems(batchID int64) error {
+	if _, err := r.db.Exec(queryBatchItemReset, batchID); err != nil {
+		return fmt.Errorf("error resetting batch items: %w", err)
+	}
+	return nil
+}
+
+func (r *batchRepository) Finish(batchID int64) error {
+	if _, err := r.db.Exec(queryBatchFinish, time.Now(), batchID); err != nil {
+		return fmt.Errorf("error finishing batch: %w", err)
+	}
+	return nil
+}
+
+func (r *batchRepository) Summary(batchID int64, worst int) (*entity.BatchSummary, error) {
+	batch, err := r.FindByID(batchID)
+	if err != nil || batch == nil {
+		return nil, err
+	}
+	summary := &entity.BatchSummary{
+		BatchID:         batch.ID,
+		Status:          batch.Status,
+		Total:           batch.Total,
+		Succeeded:       batch.Succeeded,
+		Failed:          batch.Failed,
+		Pending:         batch.Pending,
+		FailuresByClass: map[string]int{},
+		WorstPages:      []entity.BatchPage{},
+	}
+
+	rows, err := r.db.Query(queryBatchFailures, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying batch failures: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var code sql.NullString
+		var count int
+		if err := rows.Scan(&code, &count); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		summary.FailuresByClass[orDefault(code.String, entity.FailureInternal)] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	if err := r.db.QueryRow(queryBatchAvgSEO, batchID).Scan(&summary.AverageSEOScore); err != nil {
+		return nil, fmt.Errorf("error averaging seo scores: %w", err)
+	}
+	if err := r.db.QueryRow(queryBatchMixedContent, batchID).Scan(&summary.MixedContent); err != nil {
+		return nil, fmt.Errorf("error counting mixed content pages: %w", err)
+	}
+
+	pages, err := r.db.Query(queryBatchWorst, batchID, worst)
+	if err != nil {
+		return nil, fmt.Errorf("error querying worst pages: %w", err)
+	}
+	defer pages.Close()
+	for pages.Next() {
+		var page entity.BatchPage
+		if err := pages.Scan(&page.URL, &page.ResultID, &page.Title, &page.SEOScore); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		summary.WorstPages = append(summary.WorstPages, page)
+	}
+	if err := pages.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return summary, nil
+}
+
+func (r *batchRepository) scanBatch(scan scanFunc) (*entity.Batch, error) {
+	batch := &entity.Batch{}
+	var kind, options, createdAt, finishedAt sql.NullString
+
+	if err := scan(
+		&batch.ID, &batch.UserID, &batch.Name, &kind, &batch.Status, &options,
+		&createdAt, &finishedAt, &batch.Total, &batch.Succeeded, &batch.Failed,
+	); err != nil {
+		return nil, err
+	}
+	batch.Pending = batch.Total - batch.Succeeded - batch.Failed
+	batch.Kind = orDefault(kind.String, entity.BatchScrape)
+
+	if options.String != "" {
+		var override entity.ScrapeOptionsOverride
+		if err := json.Unmarshal([]byte(options.String), &override); err == nil {
+			batch.Options = &override
+		}
+	}
+
+	var err error
+	if createdAt.Valid {
+		if batch.CreatedAt, err = datetime.Parse(createdAt.String); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %w", err)
+		}
+	}
+	if finishedAt.Valid {
+		t, err := datetime.Parse(finishedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing finished_at: %w", err)
+		}
+		batch.FinishedAt = &t
+	}
+	return batch, nil
+}
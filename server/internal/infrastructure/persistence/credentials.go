@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/pkg/crypto"
+)
+
+// encryptCredentials serializes and encrypts creds for storage. Empty
+// credentials are stored as an empty string.
+func encryptCredentials(cipher *crypto.Cipher, creds *entity.RequestCredentials) (string, error) {
+	if creds.IsEmpty() {
+		return "", nil
+	}
+	return encryptJSON(cipher, creds)
+}
+
+func decryptCredentials(cipher *crypto.Cipher, value string) (*entity.RequestCredentials, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var creds entity.RequestCredentials
+	if err := decryptJSON(cipher, value, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+func encryptJSON(cipher *crypto.Cipher, v interface{}) (string, error) {
+	if cipher == nil {
+		return "", fmt.Errorf("no encryption key configured for credentials")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling credentials: %w", err)
+	}
+	encrypted, err := cipher.Encrypt(data)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting credentials: %w", err)
+	}
+	return encrypted, nil
+}
+
+func decryptJSON(cipher *crypto.Cipher, value string, v interface{}) error {
+	if cipher == nil {
+		return fmt.Errorf("no encryption key configured for credentials")
+	}
+	data, err := cipher.Decrypt(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error unmarshaling credentials: %w", err)
+	}
+	return nil
+}
@@ -2,24 +2,27 @@ package persistence
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 	"webscraper-v2/internal/domain/entity"
 	"webscraper-v2/internal/domain/repository"
 	"webscraper-v2/internal/infrastructure/database"
+	"webscraper-v2/pkg/crypto"
 	"webscraper-v2/pkg/datetime"
 )
 
 const (
-	queryScheduleCreate = `INSERT INTO schedules (user_id, name, url, cron_expression, active, last_run, next_run, run_count, created_at, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	queryScheduleFindByID = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, created_at, updated_at 
+	queryScheduleCreate = `INSERT INTO schedules (user_id, name, url, cron_expression, active, last_run, next_run, run_count, options, credentials, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	queryScheduleFindByID = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, options, credentials, created_at, updated_at 
 			  FROM schedules WHERE id = ?`
-	queryScheduleFindByUserID = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, created_at, updated_at 
+	queryScheduleFindByUserID = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, options, credentials, created_at, updated_at 
 			  FROM schedules WHERE user_id = ? ORDER BY created_at DESC`
-	queryScheduleFindActive = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, created_at, updated_at 
+	queryScheduleFindActive = `SELECT id, user_id, name, url, cron_expression, active, last_run, next_run, run_count, options, credentials, created_at, updated_at 
 			  FROM schedules WHERE active = true ORDER BY next_run ASC`
-	queryScheduleUpdate = `UPDATE schedules SET name = ?, url = ?, cron_expression = ?, active = ?, updated_at = ? 
+	queryScheduleUpdate = `UPDATE schedules SET name = ?, url = ?, cron_expression = ?, active = ?, options = ?, credentials = ?, updated_at = ? 
 			  WHERE id = ?`
 	queryScheduleDelete        = `DELETE FROM schedules WHERE id = ?`
 	queryScheduleUpdateLastRun = `UPDATE schedules SET last_run = ?, run_count = ?, updated_at = ? WHERE id = ?`
@@ -27,11 +30,13 @@ const (
 )
 
 type scheduleRepository struct {
-	db *database.SQLiteDB
+	db     *database.SQLiteDB
+	cipher *crypto.Cipher
 }
 
-func NewScheduleRepository(db *database.SQLiteDB) repository.ScheduleRepository {
-	return &scheduleRepository{db: db}
+// NewScheduleRepository stores schedule credentials encrypted with cipher.
+func NewScheduleRepository(db *database.SQLiteDB, cipher *crypto.Cipher) repository.ScheduleRepository {
+	return &scheduleRepository{db: db, cipher: cipher}
 }
 
 func (r *scheduleRepository) Create(schedule *entity.Schedule) error {
@@ -39,10 +44,19 @@ func (r *scheduleRepository) Create(schedule *entity.Schedule) error {
 	schedule.CreatedAt = now
 	schedule.UpdatedAt = now
 
+	optionsJSON, err := r.marshalOptions(schedule.Options)
+	if err != nil {
+		return err
+	}
+	credentials, err := encryptCredentials(r.cipher, schedule.Credentials)
+	if err != nil {
+		return err
+	}
+
 	res, err := r.db.Exec(queryScheduleCreate,
 		schedule.UserID, schedule.Name, schedule.URL, schedule.CronExpr,
 		schedule.Active, schedule.LastRun, schedule.NextRun, schedule.RunCount,
-		schedule.CreatedAt, schedule.UpdatedAt)
+		optionsJSON, credentials, schedule.CreatedAt, schedule.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("error creating schedule: %w", err)
@@ -58,12 +72,12 @@ func (r *scheduleRepository) Create(schedule *entity.Schedule) error {
 
 func (r *scheduleRepository) FindByID(id int64) (*entity.Schedule, error) {
 	schedule := &entity.Schedule{}
-	var lastRun, nextRun, createdAt, updatedAt sql.NullString
+	var lastRun, nextRun, options, credentials, createdAt, updatedAt sql.NullString
 
 	err := r.db.QueryRow(queryScheduleFindByID, id).Scan(
 		&schedule.ID, &schedule.UserID, &schedule.Name, &schedule.URL,
 		&schedule.CronExpr, &schedule.Active, &lastRun, &nextRun,
-		&schedule.RunCount, &createdAt, &updatedAt)
+		&schedule.RunCount, &options, &credentials, &createdAt, &updatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,6 +89,8 @@ func (r *scheduleRepository) FindByID(id int64) (*entity.Schedule, error) {
 	if err := r.parseTimestamps(schedule, lastRun, nextRun, createdAt, updatedAt); err != nil {
 		return nil, err
 	}
+	schedule.Options = r.unmarshalOptions(options)
+	schedule.Credentials = r.loadCredentials(schedule.ID, credentials)
 
 	return schedule, nil
 }
@@ -89,9 +105,18 @@ func (r *scheduleRepository) FindActiveSchedules() ([]*entity.Schedule, error) {
 
 func (r *scheduleRepository) Update(schedule *entity.Schedule) error {
 	schedule.UpdatedAt = time.Now()
-	_, err := r.db.Exec(queryScheduleUpdate,
+	optionsJSON, err := r.marshalOptions(schedule.Options)
+	if err != nil {
+		return err
+	}
+	credentials, err := encryptCredentials(r.cipher, schedule.Credentials)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(queryScheduleUpdate,
 		schedule.Name, schedule.URL, schedule.CronExpr, schedule.Active,
-		schedule.UpdatedAt, schedule.ID)
+		optionsJSON, credentials, schedule.UpdatedAt, schedule.ID)
 
 	if err != nil {
 		return fmt.Errorf("error updating schedule: %w", err)
@@ -137,7 +162,7 @@ func (r *scheduleRepository) findSchedules(query string, args ...interface{}) ([
 
 	for rows.Next() {
 		schedule := &entity.Schedule{}
-		var lastRun, nextRun, createdAt, updatedAt sql.NullString
+		var lastRun, nextRun, options, credentials, createdAt, updatedAt sql.NullString
 
 		err := rows.Scan(
 			&schedule.ID,
@@ -149,6 +174,8 @@ func (r *scheduleRepository) findSchedules(query string, args ...interface{}) ([
 			&lastRun,
 			&nextRun,
 			&schedule.RunCount,
+			&options,
+			&credentials,
 			&createdAt,
 			&updatedAt)
 
@@ -158,6 +185,8 @@ func (r *scheduleRepository) findSchedules(query string, args ...interface{}) ([
 		if err := r.parseTimestamps(schedule, lastRun, nextRun, createdAt, updatedAt); err != nil {
 			return nil, err
 		}
+		schedule.Options = r.unmarshalOptions(options)
+		schedule.Credentials = r.loadCredentials(schedule.ID, credentials)
 		schedules = append(schedules, schedule)
 	}
 
@@ -204,3 +233,40 @@ func (r *scheduleRepository) parseTimestamps(schedule *entity.Schedule, lastRun,
 
 	return nil
 }
+
+func (r *scheduleRepository) marshalOptions(options *entity.ScrapeOptionsOverride) (string, error) {
+	if options == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling options: %w", err)
+	}
+	return string(data), nil
+}
+
+func (r *scheduleRepository) unmarshalOptions(options sql.NullString) *entity.ScrapeOptionsOverride {
+	if !options.Valid || options.String == "" {
+		return nil
+	}
+	var override entity.ScrapeOptionsOverride
+	if err := json.Unmarshal([]byte(options.String), &override); err != nil {
+		return nil
+	}
+	return &override
+}
+
+// loadCredentials decrypts stored credentials. A value that cannot be
+// decrypted (e.g. after the encryption key changed) is logged and dropped
+// instead of failing the whole query.
+func (r *scheduleRepository) loadCredentials(scheduleID int64, value sql.NullString) *entity.RequestCredentials {
+	if !value.Valid {
+		return nil
+	}
+	creds, err := decryptCredentials(r.cipher, value.String)
+	if err != nil {
+		log.Printf("⚠️  Could not decrypt credentials of schedule %d: %v", scheduleID, err)
+		return nil
+	}
+	return creds
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"fmt"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/database"
+	"webscraper-v2/pkg/datetime"
+)
+
+const (
+	queryFailureSave = `INSERT INTO scrape_failures (user_id, url, attempt, code, message, status_code, retried, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	// code y url vacíos no filtran
+	queryFailureFilter = ` FROM scrape_failures WHERE user_id = ? AND (? = '' OR code = ?) AND (? = '' OR url = ?)`
+	queryFailureFind   = `SELECT id, user_id, url, attempt, code, message, status_code, retried, created_at` +
+		queryFailureFilter + ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	queryFailureCount = `SELECT COUNT(*)` + queryFailureFilter
+)
+
+type scrapeFailureRepository struct {
+	db *database.SQLiteDB
+}
+
+func NewScrapeFailureRepository(db *database.SQLiteDB) repository.ScrapeFailureRepository {
+	return &scrapeFailureRepository{db: db}
+}
+
+func (r *scrapeFailureRepository) Save(failure *entity.ScrapeFailure) error {
+	res, err := r.db.Exec(queryFailureSave,
+		failure.UserID, failure.URL, failure.Attempt, failure.Code,
+		failure.Message, failure.StatusCode, failure.Retried, failure.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving scrape failure: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+	failure.ID = id
+	return nil
+}
+
+func (r *scrapeFailureRepository) FindByUserIDPaginated(userID int64, code, url string, pagination *entity.PaginationRequest) ([]*entity.ScrapeFailure, int64, error) {
+	var total int64
+	if err := r.db.QueryRow(queryFailureCount, userID, code, code, url, url).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting scrape failures: %w", err)
+	}
+
+	rows, err := r.db.Query(queryFailureFind, userID, code, code, url, url, pagination.PerPage, pagination.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("error querying scrape failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := []*entity.ScrapeFailure{}
+	for rows.Next() {
+		failure := &entity.ScrapeFailure{}
+		var createdAt string
+		if err := rows.Scan(&failure.ID, &failure.UserID, &failure.URL, &failure.Attempt,
+			&failure.Code, &failure.Message, &failure.StatusCode, &failure.Retried, &createdAt); err != nil {
+			return nil, 0, fmt.Errorf("error scanning row: %w", err)
+		}
+		if failure.CreatedAt, err = datetime.Parse(createdAt); err != nil {
+			return nil, 0, fmt.Errorf("error parsing created_at: %w", err)
+		}
+		failures = append(failures, failure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return failures, total, nil
+}
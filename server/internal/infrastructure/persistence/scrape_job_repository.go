@@ -0,0 +1,178 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/database"
+	"webscraper-v2/pkg/crypto"
+	"webscraper-v2/pkg/datetime"
+)
+
+const (
+	queryJobCreate = `INSERT INTO scrape_jobs (user_id, url, options, credentials, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	queryJobFindByID = `SELECT id, user_id, url, options, credentials, status, result_id, error, created_at, started_at, finished_at
+			  FROM scrape_jobs WHERE id = ?`
+	// a single statement, so two workers can never claim the same job
+	queryJobClaimNext = `UPDATE scrape_jobs SET status = 'running', started_at = ?
+			  WHERE id = (SELECT id FROM scrape_jobs WHERE status = 'pending' ORDER BY id LIMIT 1)
+			  RETURNING id`
+	queryJobFinish        = `UPDATE scrape_jobs SET status = ?, result_id = ?, error = ?, finished_at = ? WHERE id = ?`
+	queryJobCancelPending = `UPDATE scrape_jobs SET status = 'cancelled', finished_at = ? WHERE id = ? AND status = 'pending'`
+	queryJobRequeue       = `UPDATE scrape_jobs SET status = 'pending', started_at = NULL WHERE status = 'running'`
+)
+
+type scrapeJobRepository struct {
+	db     *database.SQLiteDB
+	cipher *crypto.Cipher
+}
+
+// NewScrapeJobRepository stores job credentials encrypted with cipher, so
+// queued jobs can still authenticate after a restart.
+func NewScrapeJobRepository(db *database.SQLiteDB, cipher *crypto.Cipher) repository.ScrapeJobRepository {
+	return &scrapeJobRepository{db: db, cipher: cipher}
+}
+
+func (r *scrapeJobRepository) Create(job *entity.ScrapeJob) error {
+	job.CreatedAt = time.Now()
+	if job.Status == "" {
+		job.Status = entity.JobPending
+	}
+
+	options := ""
+	if job.Options != nil {
+		data, err := json.Marshal(job.Options)
+		if err != nil {
+			return fmt.Errorf("error marshaling options: %w", err)
+		}
+		options = string(data)
+	}
+	credentials, err := encryptCredentials(r.cipher, job.Credentials)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.Exec(queryJobCreate,
+		job.UserID, job.URL, options, credentials, job.Status, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating scrape job: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+	job.ID = id
+	return nil
+}
+
+func (r *scrapeJobRepository) FindByID(id int64) (*entity.ScrapeJob, error) {
+	job, err := r.scanJob(r.db.QueryRow(queryJobFindByID, id).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding scrape job by id: %w", err)
+	}
+	return job, nil
+}
+
+func (r *scrapeJobRepository) ClaimNext() (*entity.ScrapeJob, error) {
+	var id int64
+	if err := r.db.QueryRow(queryJobClaimNext, time.Now()).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error claiming scrape job: %w", err)
+	}
+	return r.FindByID(id)
+}
+
+func (r *scrapeJobRepository) Finish(job *entity.ScrapeJob) error {
+	if _, err := r.db.Exec(queryJobFinish, job.Status, job.ResultID, job.Error, job.FinishedAt, job.ID); err != nil {
+		return fmt.Errorf("error finishing scrape job: %w", err)
+	}
+	return nil
+}
+
+func (r *scrapeJobRepository) CancelPending(id int64) (bool, error) {
+	res, err := r.db.Exec(queryJobCancelPending, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("error cancelling scrape job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *scrapeJobRepository) Requeue() (int64, error) {
+	res, err := r.db.Exec(queryJobRequeue)
+	if err != nil {
+		return 0, fmt.Errorf("error requeueing scrape jobs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *scrapeJobRepository) scanJob(scan scanFunc) (*entity.ScrapeJob, error) {
+	job := &entity.ScrapeJob{}
+	var options, credentials, jobErr, createdAt, startedAt, finishedAt sql.NullString
+	var resultID sql.NullInt64
+
+	if err := scan(
+		&job.ID, &job.UserID, &job.URL, &options, &credentials, &job.Status,
+		&resultID, &jobErr, &createdAt, &startedAt, &finishedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if options.String != "" {
+		var override entity.ScrapeOptionsOverride
+		if err := json.Unmarshal([]byte(options.String), &override); err == nil {
+			job.Options = &override
+		}
+	}
+	if credentials.String != "" {
+		creds, err := decryptCredentials(r.cipher, credentials.String)
+		if err != nil {
+			log.Printf("⚠️  Could not decrypt credentials of scrape job %d: %v", job.ID, err)
+		} else {
+			job.Credentials = creds
+		}
+	}
+	if resultID.Valid {
+		job.ResultID = &resultID.Int64
+	}
+	job.Error = jobErr.String
+
+	var err error
+	if createdAt.Valid {
+		if job.CreatedAt, err = datetime.Parse(createdAt.String); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %w", err)
+		}
+	}
+	if startedAt.Valid {
+		t, err := datetime.Parse(startedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing started_at: %w", err)
+		}
+		job.StartedAt = &t
+		job.QueueMs = t.Sub(job.CreatedAt).Milliseconds()
+	}
+	if finishedAt.Valid {
+		t, err := datetime.Parse(finishedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing finished_at: %w", err)
+		}
+		job.FinishedAt = &t
+		if job.StartedAt != nil {
+			job.DurationMs = t.Sub(*job.StartedAt).Milliseconds()
+		}
+	}
+	return job, nil
+}
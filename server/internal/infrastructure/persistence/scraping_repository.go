@@ -4,13 +4,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 	"webscraper-v2/internal/domain/entity"
 	"webscraper-v2/internal/domain/repository"
 	"webscraper-v2/internal/infrastructure/database"
 	"webscraper-v2/pkg/datetime"
 )
 
-// Columnas SELECT en el mismo orden que populateResult las escanea (31 columnas).
+// Columnas SELECT en el mismo orden que populateResult las escanea (55 columnas).
 const selectCols = `
 	id, user_id, url,
 	title, description, keywords,
@@ -22,7 +23,22 @@ const selectCols = `
 	canonical_url, robots_directive, x_robots_tag,
 	viewport, og_data, twitter_card,
 	schema_org, redirect_chain, final_url,
-	h1_count, has_multiple_h1, seo_score`
+	h1_count, has_multiple_h1, seo_score,
+	icons, manifest, extensions,
+	proxy,
+	body_bytes, truncated,
+	tables,
+	simhash,
+	redirect_hops, redirect_issues,
+	client_redirects, seo_issues,
+	mixed_content, mixed_content_count,
+	resources, page_weight,
+	technologies,
+	third_party_scripts, cookies, consent_platform,
+	contacts,
+	article,
+	forms,
+	internal_links`
 
 const (
 	queryScrapingSave = `INSERT INTO scraping_results (
@@ -31,8 +47,23 @@ const (
 		content_type, word_count, load_time_ms, created_at,
 		canonical_url, robots_directive, x_robots_tag, viewport,
 		og_data, twitter_card, schema_org, redirect_chain,
-		final_url, h1_count, has_multiple_h1, seo_score
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		final_url, h1_count, has_multiple_h1, seo_score,
+		icons, manifest, extensions,
+		proxy,
+		body_bytes, truncated,
+		tables,
+		simhash,
+		redirect_hops, redirect_issues,
+		client_redirects, seo_issues,
+		mixed_content, mixed_content_count,
+		resources, page_weight,
+		technologies,
+		third_party_scripts, cookies, consent_platform,
+		contacts,
+		article, published_at,
+		forms,
+		internal_links
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	queryScrapingFindAll = `SELECT` + selectCols + `
 	FROM scraping_results ORDER BY created_at DESC`
@@ -48,7 +79,22 @@ const (
 	queryScrapingFindPaginated = `SELECT` + selectCols + `
 	FROM scraping_results WHERE user_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?`
 
+	// results without a publication date go last
+	queryScrapingFindPaginatedByPublished = `SELECT` + selectCols + `
+	FROM scraping_results WHERE user_id = ?
+	ORDER BY published_at IS NULL, published_at DESC, created_at DESC LIMIT ? OFFSET ?`
+
 	queryScrapingCount = `SELECT COUNT(*) FROM scraping_results WHERE user_id = ?`
+
+	queryScrapingFingerprints = `SELECT id, url, COALESCE(final_url, ''), COALESCE(title, ''),
+		COALESCE(description, ''), COALESCE(headers, ''), COALESCE(word_count, 0), COALESCE(simhash, '')
+	FROM scraping_results WHERE user_id = ? ORDER BY created_at DESC, id DESC`
+
+	queryScrapingByTechnology = `SELECT r.id, r.url, COALESCE(r.final_url, ''), COALESCE(r.title, ''),
+		COALESCE(json_extract(t.value, '$.version'), ''), COALESCE(json_extract(t.value, '$.confidence'), 0), r.created_at
+	FROM scraping_results r, json_each(COALESCE(r.technologies, '[]')) t
+	WHERE r.user_id = ? AND lower(json_extract(t.value, '$.name')) = lower(?)
+	ORDER BY r.created_at DESC, r.id DESC`
 )
 
 type scrapingRepository struct {
@@ -88,6 +134,83 @@ func (r *scrapingRepository) Save(result *entity.ScrapingResult) error {
 	if err != nil {
 		return fmt.Errorf("error marshaling redirect_chain: %w", err)
 	}
+	iconsJSON, err := json.Marshal(result.Icons)
+	if err != nil {
+		return fmt.Errorf("error marshaling icons: %w", err)
+	}
+	manifestJSON, err := json.Marshal(result.Manifest)
+	if err != nil {
+		return fmt.Errorf("error marshaling manifest: %w", err)
+	}
+	extensionsJSON, err := json.Marshal(result.Extensions)
+	if err != nil {
+		return fmt.Errorf("error marshaling extensions: %w", err)
+	}
+	tablesJSON, err := json.Marshal(result.Tables)
+	if err != nil {
+		return fmt.Errorf("error marshaling tables: %w", err)
+	}
+	redirectHopsJSON, err := json.Marshal(result.RedirectHops)
+	if err != nil {
+		return fmt.Errorf("error marshaling redirect_hops: %w", err)
+	}
+	redirectIssuesJSON, err := json.Marshal(result.RedirectIssues)
+	if err != nil {
+		return fmt.Errorf("error marshaling redirect_issues: %w", err)
+	}
+	clientRedirectsJSON, err := json.Marshal(result.ClientRedirects)
+	if err != nil {
+		return fmt.Errorf("error marshaling client_redirects: %w", err)
+	}
+	seoIssuesJSON, err := json.Marshal(result.SEOIssues)
+	if err != nil {
+		return fmt.Errorf("error marshaling seo_issues: %w", err)
+	}
+	mixedContentJSON, err := json.Marshal(result.MixedContent)
+	if err != nil {
+		return fmt.Errorf("error marshaling mixed_content: %w", err)
+	}
+	resourcesJSON, err := json.Marshal(result.Resources)
+	if err != nil {
+		return fmt.Errorf("error marshaling resources: %w", err)
+	}
+	pageWeightJSON, err := json.Marshal(result.PageWeight)
+	if err != nil {
+		return fmt.Errorf("error marshaling page_weight: %w", err)
+	}
+	technologiesJSON, err := json.Marshal(result.Technologies)
+	if err != nil {
+		return fmt.Errorf("error marshaling technologies: %w", err)
+	}
+	thirdPartyScriptsJSON, err := json.Marshal(result.ThirdPartyScripts)
+	if err != nil {
+		return fmt.Errorf("error marshaling third_party_scripts: %w", err)
+	}
+	cookiesJSON, err := json.Marshal(result.Cookies)
+	if err != nil {
+		return fmt.Errorf("error marshaling cookies: %w", err)
+	}
+	contactsJSON, err := json.Marshal(result.Contacts)
+	if err != nil {
+		return fmt.Errorf("error marshaling contacts: %w", err)
+	}
+	articleJSON, err := json.Marshal(result.Article)
+	if err != nil {
+		return fmt.Errorf("error marshaling article: %w", err)
+	}
+	// published_at is kept apart, in UTC, so that results sort by it
+	var publishedAt any
+	if result.Article.PublishedAt != nil {
+		publishedAt = result.Article.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	formsJSON, err := json.Marshal(result.Forms)
+	if err != nil {
+		return fmt.Errorf("error marshaling forms: %w", err)
+	}
+	internalLinksJSON, err := json.Marshal(result.InternalLinks)
+	if err != nil {
+		return fmt.Errorf("error marshaling internal links: %w", err)
+	}
 
 	res, err := r.db.Exec(queryScrapingSave,
 		result.UserID, result.URL, result.Title, result.Description,
@@ -98,6 +221,21 @@ func (r *scrapingRepository) Save(result *entity.ScrapingResult) error {
 		result.CanonicalURL, result.RobotsDirective, result.XRobotsTag, result.Viewport,
 		string(ogDataJSON), string(twitterCardJSON), string(schemaOrgJSON), string(redirectChainJSON),
 		result.FinalURL, result.H1Count, result.HasMultipleH1, result.SEOScore,
+		string(iconsJSON), string(manifestJSON), string(extensionsJSON),
+		result.Proxy,
+		result.BodyBytes, result.Truncated,
+		string(tablesJSON),
+		result.SimHash,
+		string(redirectHopsJSON), string(redirectIssuesJSON),
+		string(clientRedirectsJSON), string(seoIssuesJSON),
+		string(mixedContentJSON), result.MixedContentCount,
+		string(resourcesJSON), string(pageWeightJSON),
+		string(technologiesJSON),
+		string(thirdPartyScriptsJSON), string(cookiesJSON), result.ConsentPlatform,
+		string(contactsJSON),
+		string(articleJSON), publishedAt,
+		string(formsJSON),
+		string(internalLinksJSON),
 	)
 	if err != nil {
 		return fmt.Errorf("error executing insert: %w", err)
@@ -147,7 +285,9 @@ func (r *scrapingRepository) Delete(id int64) error {
 	return nil
 }
 
-func (r *scrapingRepository) FindAllByUserIDPaginated(userID int64, pagination *entity.PaginationRequest) ([]*entity.ScrapingResult, int64, error) {
+// FindAllByUserIDPaginated lists the results of a user, newest first or,
+// with sort published_at, by publication date.
+func (r *scrapingRepository) FindAllByUserIDPaginated(userID int64, sort string, pagination *entity.PaginationRequest) ([]*entity.ScrapingResult, int64, error) {
 	totalCount, err := r.CountByUserID(userID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting results: %w", err)
@@ -155,7 +295,11 @@ func (r *scrapingRepository) FindAllByUserIDPaginated(userID int64, pagination *
 	if totalCount == 0 {
 		return []*entity.ScrapingResult{}, 0, nil
 	}
-	rows, err := r.db.Query(queryScrapingFindPaginated, userID, pagination.PerPage, pagination.Offset())
+	query := queryScrapingFindPaginated
+	if sort == entity.ResultSortPublished {
+		query = queryScrapingFindPaginatedByPublished
+	}
+	rows, err := r.db.Query(query, userID, pagination.PerPage, pagination.Offset())
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying paginated results: %w", err)
 	}
@@ -176,6 +320,68 @@ func (r *scrapingRepository) CountByUserID(userID int64) (int64, error) {
 	return count, nil
 }
 
+// FindFingerprints lists the fields used to compare the results of a user,
+// newest first, without loading the whole rows.
+func (r *scrapingRepository) FindFingerprints(userID int64) ([]*entity.PageFingerprint, error) {
+	rows, err := r.db.Query(queryScrapingFingerprints, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	pages := []*entity.PageFingerprint{}
+	for rows.Next() {
+		page := &entity.PageFingerprint{}
+		var headersJSON string
+		if err := rows.Scan(&page.ResultID, &page.URL, &page.FinalURL, &page.Title,
+			&page.Description, &headersJSON, &page.WordCount, &page.SimHash); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		var headers []entity.Header
+		if err := r.unmarshalJSONField(headersJSON, &headers); err == nil {
+			for _, header := range headers {
+				if header.Level == 1 {
+					page.H1 = header.Text
+					break
+				}
+			}
+		}
+		pages = append(pages, page)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return pages, nil
+}
+
+// FindByTechnology lists the results of a user on which the named
+// technology was detected, newest first.
+func (r *scrapingRepository) FindByTechnology(userID int64, name string) ([]*entity.TechnologyUsage, error) {
+	rows, err := r.db.Query(queryScrapingByTechnology, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("error querying technology usage: %w", err)
+	}
+	defer rows.Close()
+
+	usages := []*entity.TechnologyUsage{}
+	for rows.Next() {
+		usage := &entity.TechnologyUsage{}
+		var createdAt string
+		if err := rows.Scan(&usage.ResultID, &usage.URL, &usage.FinalURL, &usage.Title,
+			&usage.Version, &usage.Confidence, &createdAt); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if usage.CreatedAt, err = datetime.Parse(createdAt); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %w", err)
+		}
+		usages = append(usages, usage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return usages, nil
+}
+
 // — Helpers —
 
 type scanFunc func(dest ...interface{}) error
@@ -187,7 +393,25 @@ func (r *scrapingRepository) populateResult(scan scanFunc) (*entity.ScrapingResu
 		linksJSON, imagesJSON, headersJSON string
 		ogDataJSON, twitterCardJSON        string
 		schemaOrgJSON, redirectChainJSON   string
+		iconsJSON, manifestJSON            string
+		extensionsJSON                     string
 		createdAt                          string
+		tablesJSON                         string
+		simhash                            sql.NullString
+		redirectHopsJSON                   sql.NullString
+		redirectIssuesJSON                 sql.NullString
+		clientRedirectsJSON                sql.NullString
+		seoIssuesJSON                      sql.NullString
+		mixedContentJSON                   sql.NullString
+		resourcesJSON, pageWeightJSON      sql.NullString
+		technologiesJSON                   sql.NullString
+		thirdPartyScriptsJSON              sql.NullString
+		cookiesJSON                        sql.NullString
+		consentPlatform                    sql.NullString
+		contactsJSON                       sql.NullString
+		articleJSON                        sql.NullString
+		formsJSON                          sql.NullString
+		internalLinksJSON                  sql.NullString
 	)
 
 	if err := scan(
@@ -202,6 +426,21 @@ func (r *scrapingRepository) populateResult(scan scanFunc) (*entity.ScrapingResu
 		&result.Viewport, &ogDataJSON, &twitterCardJSON,
 		&schemaOrgJSON, &redirectChainJSON, &result.FinalURL,
 		&result.H1Count, &result.HasMultipleH1, &result.SEOScore,
+		&iconsJSON, &manifestJSON, &extensionsJSON,
+		&result.Proxy,
+		&result.BodyBytes, &result.Truncated,
+		&tablesJSON,
+		&simhash,
+		&redirectHopsJSON, &redirectIssuesJSON,
+		&clientRedirectsJSON, &seoIssuesJSON,
+		&mixedContentJSON, &result.MixedContentCount,
+		&resourcesJSON, &pageWeightJSON,
+		&technologiesJSON,
+		&thirdPartyScriptsJSON, &cookiesJSON, &consentPlatform,
+		&contactsJSON,
+		&articleJSON,
+		&formsJSON,
+		&internalLinksJSON,
 	); err != nil {
 		return nil, err
 	}
@@ -224,6 +463,78 @@ func (r *scrapingRepository) populateResult(scan scanFunc) (*entity.ScrapingResu
 	if err := r.unmarshalJSONField(redirectChainJSON, &result.RedirectChain); err != nil {
 		result.RedirectChain = []string{}
 	}
+	if err := r.unmarshalJSONField(iconsJSON, &result.Icons); err != nil {
+		result.Icons = []entity.Icon{}
+	}
+	if err := json.Unmarshal([]byte(orDefault(manifestJSON, "null")), &result.Manifest); err != nil {
+		result.Manifest = nil
+	}
+	if err := json.Unmarshal([]byte(orDefault(extensionsJSON, "null")), &result.Extensions); err != nil {
+		result.Extensions = nil
+	}
+	if err := r.unmarshalJSONField(tablesJSON, &result.Tables); err != nil || result.Tables == nil {
+		result.Tables = []entity.Table{}
+	}
+	result.SimHash = simhash.String
+	if err := r.unmarshalJSONField(redirectHopsJSON.String, &result.RedirectHops); err != nil || result.RedirectHops == nil {
+		result.RedirectHops = []entity.RedirectHop{}
+	}
+	if err := r.unmarshalJSONField(redirectIssuesJSON.String, &result.RedirectIssues); err != nil || result.RedirectIssues == nil {
+		result.RedirectIssues = []entity.RedirectIssue{}
+	}
+	if err := r.unmarshalJSONField(clientRedirectsJSON.String, &result.ClientRedirects); err != nil || result.ClientRedirects == nil {
+		result.ClientRedirects = []entity.ClientRedirect{}
+	}
+	if err := r.unmarshalJSONField(seoIssuesJSON.String, &result.SEOIssues); err != nil || result.SEOIssues == nil {
+		result.SEOIssues = []entity.SEOIssue{}
+	}
+	if err := r.unmarshalJSONField(mixedContentJSON.String, &result.MixedContent); err != nil || result.MixedContent == nil {
+		result.MixedContent = []entity.MixedContent{}
+	}
+	if err := r.unmarshalJSONField(resourcesJSON.String, &result.Resources); err != nil || result.Resources == nil {
+		result.Resources = []entity.Resource{}
+	}
+	if err := json.Unmarshal([]byte(orDefault(pageWeightJSON.String, "null")), &result.PageWeight); err != nil {
+		result.PageWeight = nil
+	}
+	if err := r.unmarshalJSONField(technologiesJSON.String, &result.Technologies); err != nil || result.Technologies == nil {
+		result.Technologies = []entity.Technology{}
+	}
+	if err := r.unmarshalJSONField(thirdPartyScriptsJSON.String, &result.ThirdPartyScripts); err != nil || result.ThirdPartyScripts == nil {
+		result.ThirdPartyScripts = []entity.ThirdPartyScript{}
+	}
+	if err := r.unmarshalJSONField(cookiesJSON.String, &result.Cookies); err != nil || result.Cookies == nil {
+		result.Cookies = []entity.Cookie{}
+	}
+	result.ConsentPlatform = consentPlatform.String
+	if err := json.Unmarshal([]byte(orDefault(contactsJSON.String, "{}")), &result.Contacts); err != nil {
+		result.Contacts = entity.Contacts{}
+	}
+	if result.Contacts.Emails == nil {
+		result.Contacts.Emails = []entity.ContactEmail{}
+	}
+	if result.Contacts.Phones == nil {
+		result.Contacts.Phones = []entity.ContactPhone{}
+	}
+	if result.Contacts.Addresses == nil {
+		result.Contacts.Addresses = []entity.PostalAddress{}
+	}
+	if result.Contacts.SocialProfiles == nil {
+		result.Contacts.SocialProfiles = []entity.SocialProfile{}
+	}
+	if err := json.Unmarshal([]byte(orDefault(articleJSON.String, "{}")), &result.Article); err != nil {
+		result.Article = entity.Article{}
+	}
+	if result.Article.Authors == nil {
+		result.Article.Authors = []string{}
+	}
+	if err := r.unmarshalJSONField(formsJSON.String, &result.Forms); err != nil || result.Forms == nil {
+		result.Forms = []entity.Form{}
+	}
+	// left nil for results saved before internal_links existed
+	if err := r.unmarshalJSONField(internalLinksJSON.String, &result.InternalLinks); err != nil {
+		result.InternalLinks = nil
+	}
 
 	var err error
 	result.CreatedAt, err = datetime.Parse(createdAt)
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/database"
+	"webscraper-v2/pkg/crypto"
+	"webscraper-v2/pkg/datetime"
+)
+
+const (
+	querySessionCreate = `INSERT INTO session_profiles (user_id, name, login_url, form_action, username_field, password_field, extra_fields, success_check, secrets, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	querySessionSelect = `SELECT id, user_id, name, login_url, form_action, username_field, password_field, extra_fields, success_check, secrets, created_at, updated_at
+			  FROM session_profiles`
+	querySessionFindByID     = querySessionSelect + ` WHERE id = ?`
+	querySessionFindByUserID = querySessionSelect + ` WHERE user_id = ? ORDER BY name ASC`
+	querySessionUpdate       = `UPDATE session_profiles SET name = ?, login_url = ?, form_action = ?, username_field = ?, password_field = ?, extra_fields = ?, success_check = ?, secrets = ?, updated_at = ?
+			  WHERE id = ?`
+	querySessionDelete = `DELETE FROM session_profiles WHERE id = ?`
+)
+
+// sessionSecrets is the encrypted part of a session profile. Extra fields
+// often hold secrets too, such as tenant keys or OTP seeds.
+type sessionSecrets struct {
+	Username    string            `json:"username"`
+	Password    string            `json:"password"`
+	ExtraFields map[string]string `json:"extra_fields,omitempty"`
+}
+
+type sessionRepository struct {
+	db     *database.SQLiteDB
+	cipher *crypto.Cipher
+}
+
+// NewSessionRepository stores the login username, password and extra fields
+// encrypted with cipher.
+func NewSessionRepository(db *database.SQLiteDB, cipher *crypto.Cipher) repository.SessionProfileRepository {
+	return &sessionRepository{db: db, cipher: cipher}
+}
+
+func (r *sessionRepository) Create(profile *entity.SessionProfile) error {
+	now := time.Now()
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+
+	successCheck, secrets, err := r.marshalProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.Exec(querySessionCreate,
+		profile.UserID, profile.Name, profile.LoginURL, profile.FormAction,
+		profile.UsernameField, profile.PasswordField, "", successCheck,
+		secrets, profile.CreatedAt, profile.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating session profile: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+	profile.ID = id
+	return nil
+}
+
+func (r *sessionRepository) FindByID(id int64) (*entity.SessionProfile, error) {
+	profile, err := r.scanProfile(r.db.QueryRow(querySessionFindByID, id).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding session profile by id: %w", err)
+	}
+	return profile, nil
+}
+
+func (r *sessionRepository) FindByUserID(userID int64) ([]*entity.SessionProfile, error) {
+	rows, err := r.db.Query(querySessionFindByUserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying session profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []*entity.SessionProfile{}
+	for rows.Next() {
+		profile, err := r.scanProfile(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *sessionRepository) Update(profile *entity.SessionProfile) error {
+	profile.UpdatedAt = time.Now()
+
+	successCheck, secrets, err := r.marshalProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(querySessionUpdate,
+		profile.Name, profile.LoginURL, profile.FormAction, profile.UsernameField,
+		profile.PasswordField, "", successCheck, secrets,
+		profile.UpdatedAt, profile.ID)
+	if err != nil {
+		return fmt.Errorf("error updating session profile: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Delete(id int64) error {
+	if _, err := r.db.Exec(querySessionDelete, id); err != nil {
+		return fmt.Errorf("error deleting session profile: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) marshalProfile(profile *entity.SessionProfile) (string, string, error) {
+	successCheck, err := json.Marshal(profile.SuccessCheck)
+	if err != nil {
+		return "", "", fmt.Errorf("error marshaling success check: %w", err)
+	}
+	secrets, err := encryptJSON(r.cipher, sessionSecrets{
+		Username:    profile.Username,
+		Password:    profile.Password,
+		ExtraFields: profile.ExtraFields,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return string(successCheck), secrets, nil
+}
+
+func (r *sessionRepository) scanProfile(scan scanFunc) (*entity.SessionProfile, error) {
+	profile := &entity.SessionProfile{}
+	var extraFields, successCheck, secrets, createdAt, updatedAt sql.NullString
+
+	if err := scan(
+		&profile.ID, &profile.UserID, &profile.Name, &profile.LoginURL,
+		&profile.FormAction, &profile.UsernameField, &profile.PasswordField,
+		&extraFields, &successCheck, &secrets, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// profiles saved before extra fields were encrypted keep them in
+	// extra_fields until they are next updated
+	if extraFields.String != "" {
+		if err := json.Unmarshal([]byte(extraFields.String), &profile.ExtraFields); err != nil {
+			profile.ExtraFields = nil
+		}
+	}
+	if err := json.Unmarshal([]byte(orDefault(successCheck.String, "{}")), &profile.SuccessCheck); err != nil {
+		profile.SuccessCheck = entity.SessionSuccessCheck{}
+	}
+	if secrets.String != "" {
+		var s sessionSecrets
+		if err := decryptJSON(r.cipher, secrets.String, &s); err != nil {
+			log.Printf("⚠️  Could not decrypt credentials of session profile %d: %v", profile.ID, err)
+		} else {
+			profile.Username = s.Username
+			profile.Password = s.Password
+			if s.ExtraFields != nil {
+				profile.ExtraFields = s.ExtraFields
+			}
+		}
+	}
+
+	var err error
+	if createdAt.Valid {
+		if profile.CreatedAt, err = datetime.Parse(createdAt.String); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %w", err)
+		}
+	}
+	if updatedAt.Valid {
+		if profile.UpdatedAt, err = datetime.Parse(updatedAt.String); err != nil {
+			return nil, fmt.Errorf("error parsing updated_at: %w", err)
+		}
+	}
+	return profile, nil
+}
@@ -0,0 +1,214 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+	"webscraper-v2/internal/infrastructure/config"
+)
+
+const (
+	StrategyRoundRobin = "round_robin"
+	StrategyRandom     = "random"
+)
+
+var ErrNoHealthyProxy = errors.New("no healthy proxy available")
+
+// Proxy is a single outbound proxy with its health counters.
+type Proxy struct {
+	URL           *url.URL
+	pool          string
+	failures      int
+	disabledUntil time.Time
+	lastError     string
+}
+
+// Redacted returns the proxy URL without credentials, safe for logs and
+// API responses.
+func (p *Proxy) Redacted() string {
+	return fmt.Sprintf("%s://%s", p.URL.Scheme, p.URL.Host)
+}
+
+func (p *Proxy) Pool() string {
+	return p.pool
+}
+
+type Pool struct {
+	name        string
+	strategy    string
+	proxies     []*Proxy
+	next        int
+	maxFailures int
+	cooldown    time.Duration
+	mu          sync.Mutex
+	rng         *rand.Rand
+}
+
+// Pick returns the next healthy proxy according to the pool strategy.
+// Proxies that failed MaxFailures times in a row are skipped until their
+// cooldown expires.
+func (p *Pool) Pick() (*Proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var healthy []int
+	for i, px := range p.proxies {
+		if px.disabledUntil.Before(now) {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("pool %s: %w", p.name, ErrNoHealthyProxy)
+	}
+
+	if p.strategy == StrategyRandom {
+		return p.proxies[healthy[p.rng.Intn(len(healthy))]], nil
+	}
+
+	for range p.proxies {
+		i := p.next % len(p.proxies)
+		p.next++
+		if p.proxies[i].disabledUntil.Before(now) {
+			return p.proxies[i], nil
+		}
+	}
+	return p.proxies[healthy[0]], nil
+}
+
+func (p *Pool) ReportSuccess(px *Proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	px.failures = 0
+	px.lastError = ""
+}
+
+func (p *Pool) ReportFailure(px *Proxy, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	px.failures++
+	if err != nil {
+		px.lastError = err.Error()
+	}
+	if px.failures >= p.maxFailures {
+		px.disabledUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+// ProxyStatus is the health snapshot exposed to administrators.
+type ProxyStatus struct {
+	URL           string     `json:"url"`
+	Healthy       bool       `json:"healthy"`
+	Failures      int        `json:"failures"`
+	DisabledUntil *time.Time `json:"disabled_until,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+type PoolStatus struct {
+	Name     string        `json:"name"`
+	Strategy string        `json:"strategy"`
+	Default  bool          `json:"default"`
+	Proxies  []ProxyStatus `json:"proxies"`
+}
+
+func (p *Pool) status() []ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]ProxyStatus, 0, len(p.proxies))
+	for _, px := range p.proxies {
+		st := ProxyStatus{
+			URL:       px.Redacted(),
+			Healthy:   px.disabledUntil.Before(now),
+			Failures:  px.failures,
+			LastError: px.lastError,
+		}
+		if !st.Healthy {
+			until := px.disabledUntil
+			st.DisabledUntil = &until
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// Manager holds the proxy pools declared in config.yaml.
+type Manager struct {
+	pools       map[string]*Pool
+	defaultPool string
+}
+
+func NewManager(cfg config.ProxyConfig) (*Manager, error) {
+	m := &Manager{
+		pools:       make(map[string]*Pool),
+		defaultPool: cfg.DefaultPool,
+	}
+
+	for name, poolCfg := range cfg.Pools {
+		if poolCfg.Strategy != StrategyRoundRobin && poolCfg.Strategy != StrategyRandom {
+			return nil, fmt.Errorf("proxy pool %s: unknown strategy %q", name, poolCfg.Strategy)
+		}
+		if len(poolCfg.URLs) == 0 {
+			return nil, fmt.Errorf("proxy pool %s: at least one proxy URL is required", name)
+		}
+
+		pool := &Pool{
+			name:        name,
+			strategy:    poolCfg.Strategy,
+			maxFailures: poolCfg.MaxFailures,
+			cooldown:    time.Duration(poolCfg.Cooldown) * time.Second,
+			rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+		for _, raw := range poolCfg.URLs {
+			u, err := url.Parse(raw)
+			if err != nil {
+				return nil, fmt.Errorf("proxy pool %s: invalid proxy URL: %w", name, err)
+			}
+			switch u.Scheme {
+			case "http", "https", "socks5", "socks5h":
+			default:
+				return nil, fmt.Errorf("proxy pool %s: unsupported proxy scheme %q", name, u.Scheme)
+			}
+			if u.Host == "" {
+				return nil, fmt.Errorf("proxy pool %s: proxy URL must include a host", name)
+			}
+			pool.proxies = append(pool.proxies, &Proxy{URL: u, pool: name})
+		}
+		m.pools[name] = pool
+	}
+
+	if m.defaultPool != "" {
+		if _, ok := m.pools[m.defaultPool]; !ok {
+			return nil, fmt.Errorf("default proxy pool %s is not defined", m.defaultPool)
+		}
+	}
+	return m, nil
+}
+
+func (m *Manager) Pool(name string) (*Pool, bool) {
+	pool, ok := m.pools[name]
+	return pool, ok
+}
+
+func (m *Manager) DefaultPool() string {
+	return m.defaultPool
+}
+
+func (m *Manager) Status() []PoolStatus {
+	statuses := make([]PoolStatus, 0, len(m.pools))
+	for name, pool := range m.pools {
+		statuses = append(statuses, PoolStatus{
+			Name:     name,
+			Strategy: pool.strategy,
+			Default:  name == m.defaultPool,
+			Proxies:  pool.status(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	"webscraper-v2/internal/usecase"
+)
+
+// maxBatchUpload caps the size of an uploaded URL list.
+const maxBatchUpload = 5 << 20
+
+type BatchHandler struct {
+	batchUseCase *usecase.BatchUseCase
+}
+
+func NewBatchHandler(batchUseCase *usecase.BatchUseCase) *BatchHandler {
+	return &BatchHandler{
+		batchUseCase: batchUseCase,
+	}
+}
+
+// Create accepts a JSON object ({"name", "urls", "options"}), a bare JSON
+// array of URLs, a text/csv or text/plain body, or a multipart upload with
+// the list in the "file" field.
+func (h *BatchHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchUpload)
+
+	req, err := h.decodeRequest(r)
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid batch request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	batch, err := h.batchUseCase.CreateBatch(req, user.ID)
+
+	if err != nil {
+		log.Printf("Error creating batch: %v", err)
+		response.SendErrorResponse(w, "Failed to create batch", http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("Batch created: %s (ID: %d) by user %s", batch.Name, batch.ID, user.Username)
+	response.SendAcceptedResponse(w, fmt.Sprintf("Batch of %d URLs queued", batch.Total), batch)
+}
+
+func (h *BatchHandler) decodeRequest(r *http.Request) (*entity.CreateBatchRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing file: %w", err)
+		}
+		defer file.Close()
+
+		format := "txt"
+		if strings.EqualFold(filepath.Ext(header.Filename), ".csv") {
+			format = "csv"
+		}
+		urls, err := usecase.ParseURLList(file, format)
+		if err != nil {
+			return nil, err
+		}
+		req := &entity.CreateBatchRequest{Name: r.FormValue("name"), URLs: urls}
+		if options := r.FormValue("options"); options != "" {
+			if err := json.Unmarshal([]byte(options), &req.Options); err != nil {
+				return nil, fmt.Errorf("invalid options: %w", err)
+			}
+		}
+		return req, nil
+
+	case "text/csv", "text/plain":
+		format := "txt"
+		if mediaType == "text/csv" {
+			format = "csv"
+		}
+		urls, err := usecase.ParseURLList(r.Body, format)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.CreateBatchRequest{Name: r.URL.Query().Get("name"), URLs: urls}, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var req entity.CreateBatchRequest
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &req.URLs)
+	} else {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
+	}
+	return &req, nil
+}
+
+func (h *BatchHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	batches, err := h.batchUseCase.GetBatches(user.ID)
+
+	if err != nil {
+		log.Printf("Error getting batches: %v", err)
+		response.SendErrorResponse(w, "Failed to retrieve batches", http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.SendSuccessResponse(w, fmt.Sprintf("Retrieved %d batches", len(batches)), batches)
+}
+
+// GetByID returns the batch with its progress.
+func (h *BatchHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	batch, err := h.batchUseCase.GetBatch(id, user.ID)
+
+	if err != nil {
+		h.sendBatchError(w, id, "Failed to retrieve batch", err)
+		return
+	}
+	response.SendSuccessResponse(w, "Batch retrieved successfully", batch)
+}
+
+func (h *BatchHandler) GetItems(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	items, err := h.batchUseCase.GetItems(id, user.ID, r.URL.Query().Get("status"))
+
+	if err != nil {
+		h.sendBatchError(w, id, "Failed to retrieve batch items", err)
+		return
+	}
+	response.SendSuccessResponse(w, fmt.Sprintf("Retrieved %d batch items", len(items)), items)
+}
+
+func (h *BatchHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	summary, err := h.batchUseCase.GetSummary(id, user.ID)
+
+	if err != nil {
+		h.sendBatchError(w, id, "Failed to retrieve batch summary", err)
+		return
+	}
+	response.SendSuccessResponse(w, "Batch summary retrieved successfully", summary)
+}
+
+func (h *BatchHandler) sendBatchError(w http.ResponseWriter, id int64, message string, err error) {
+	log.Printf("Error with batch %d: %v", id, err)
+
+	if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unauthorized") {
+		response.SendErrorResponse(w, "Batch not found", http.StatusNotFound, fmt.Sprintf("No batch found with ID %d", id))
+		return
+	}
+	response.SendErrorResponse(w, message, http.StatusInternalServerError, err.Error())
+}
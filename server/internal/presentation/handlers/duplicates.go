@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	"webscraper-v2/internal/usecase"
+	pkgerrors "webscraper-v2/pkg/errors"
+)
+
+// GetDuplicates reports near-duplicate and thin pages and repeated titles,
+// descriptions and H1s. Accepts the host, threshold (0.5-1) and thin_words
+// query parameters.
+func (h *ScrapingHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	query := r.URL.Query()
+	threshold := usecase.DefaultDuplicateThreshold
+	if v := query.Get("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			response.SendErrorResponse(w, "Invalid threshold", http.StatusBadRequest, "Threshold must be a number between 0.5 and 1")
+			return
+		}
+		threshold = t
+	}
+	thinWords := usecase.DefaultThinWordCount
+	if v := query.Get("thin_words"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			response.SendErrorResponse(w, "Invalid thin_words", http.StatusBadRequest, "thin_words must be a whole number")
+			return
+		}
+		thinWords = n
+	}
+
+	report, err := h.scrapingUseCase.GetDuplicates(user.ID, query.Get("host"), threshold, thinWords)
+	if err != nil {
+		log.Printf("Error finding duplicates by user %s: %v", user.Username, err)
+
+		if errors.Is(err, pkgerrors.ErrInvalidInput) {
+			response.SendErrorResponse(w, "Invalid duplicates request", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		response.SendErrorResponse(w, "Failed to find duplicates", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.SendSuccessResponse(w, fmt.Sprintf("Found %d near-duplicate clusters", len(report.Clusters)), report)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	"webscraper-v2/internal/usecase"
+)
+
+type JobHandler struct {
+	jobUseCase *usecase.JobUseCase
+}
+
+func NewJobHandler(jobUseCase *usecase.JobUseCase) *JobHandler {
+	return &JobHandler{
+		jobUseCase: jobUseCase,
+	}
+}
+
+// Submit queues a scrape and answers 202 with the pending job.
+func (h *JobHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	var req entity.ScrapeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.SendErrorResponse(w, "Invalid JSON format", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.jobUseCase.Submit(&req, user.ID)
+
+	if err != nil {
+		log.Printf("Error queueing scrape of %s: %v", req.URL, err)
+		response.SendErrorResponse(w, "Failed to queue scrape", http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("Queued scrape job %d: %s", job.ID, job.URL)
+	response.SendAcceptedResponse(w, "Scrape queued", job)
+}
+
+func (h *JobHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	job, err := h.jobUseCase.GetJob(id, user.ID)
+
+	if err != nil {
+		h.sendJobError(w, id, "Failed to retrieve job", http.StatusInternalServerError, err)
+		return
+	}
+	response.SendSuccessResponse(w, "Job retrieved successfully", job)
+}
+
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	job, err := h.jobUseCase.CancelJob(id, user.ID)
+
+	if err != nil {
+		h.sendJobError(w, id, "Failed to cancel job", http.StatusConflict, err)
+		return
+	}
+	response.SendSuccessResponse(w, "Job cancellation requested", job)
+}
+
+func (h *JobHandler) sendJobError(w http.ResponseWriter, id int64, message string, status int, err error) {
+	log.Printf("Error with job %d: %v", id, err)
+
+	if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unauthorized") {
+		response.SendErrorResponse(w, "Job not found", http.StatusNotFound, fmt.Sprintf("No job found with ID %d", id))
+		return
+	}
+	response.SendErrorResponse(w, message, status, err.Error())
+}
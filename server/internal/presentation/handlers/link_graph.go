@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+)
+
+// GetLinkGraph returns the internal link graph of ?host=, as JSON by default
+// or as a GraphML file with ?format=graphml.
+func (h *ScrapingHandler) GetLinkGraph(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "graphml" {
+		response.SendErrorResponse(w, "Invalid format", http.StatusBadRequest, "Format must be json or graphml")
+		return
+	}
+
+	graph, err := h.scrapingUseCase.GetLinkGraph(user.ID, r.URL.Query().Get("host"))
+	if err != nil {
+		log.Printf("Error building link graph by user %s: %v", user.Username, err)
+
+		if strings.Contains(err.Error(), "host is required") {
+			response.SendErrorResponse(w, "Host is required", http.StatusBadRequest, "Pass the site as ?host=")
+			return
+		}
+
+		response.SendErrorResponse(w, "Failed to build link graph", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format != "graphml" {
+		response.SendSuccessResponse(w, fmt.Sprintf("Link graph with %d pages and %d links", len(graph.Nodes), len(graph.Edges)), graph)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/graphml+xml; charset=utf-8")
+	// the host comes from the query string: let mime quote it
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": graph.Host + "-links.graphml",
+	}))
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(newGraphML(graph)); err != nil {
+		log.Printf("Error writing link graph of %s: %v", graph.Host, err)
+	}
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// newGraphML converts graph to GraphML, with the page URLs as node ids.
+func newGraphML(graph *entity.LinkGraph) graphML {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "title", For: "node", Name: "title", Type: "string"},
+			{ID: "status_code", For: "node", Name: "status_code", Type: "int"},
+			{ID: "inlinks", For: "node", Name: "inlinks", Type: "int"},
+			{ID: "outlinks", For: "node", Name: "outlinks", Type: "int"},
+			{ID: "depth", For: "node", Name: "depth", Type: "int"},
+			{ID: "pagerank", For: "node", Name: "pagerank", Type: "double"},
+			{ID: "orphan", For: "node", Name: "orphan", Type: "boolean"},
+			{ID: "dead_end", For: "node", Name: "dead_end", Type: "boolean"},
+			{ID: "anchor", For: "edge", Name: "anchor", Type: "string"},
+		},
+		Graph: graphMLGraph{ID: graph.Host, EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.URL,
+			Data: []graphMLData{
+				{Key: "title", Value: node.Title},
+				{Key: "status_code", Value: strconv.Itoa(node.StatusCode)},
+				{Key: "inlinks", Value: strconv.Itoa(node.Inlinks)},
+				{Key: "outlinks", Value: strconv.Itoa(node.Outlinks)},
+				{Key: "depth", Value: strconv.Itoa(node.Depth)},
+				{Key: "pagerank", Value: strconv.FormatFloat(node.PageRank, 'g', 6, 64)},
+				{Key: "orphan", Value: strconv.FormatBool(node.Orphan)},
+				{Key: "dead_end", Value: strconv.FormatBool(node.DeadEnd)},
+			},
+		})
+	}
+	for _, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.Source,
+			Target: edge.Target,
+			Data:   []graphMLData{{Key: "anchor", Value: edge.Anchor}},
+		})
+	}
+	return doc
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+)
+
+// GetPrivacyReport exports the privacy audit of a result: third-party
+// scripts, first-load cookies and consent platform, as JSON or, with
+// ?format=csv, as one CSV row per consent platform, script host and cookie.
+func (h *ScrapingHandler) GetPrivacyReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	id, err := parseID(r)
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		response.SendErrorResponse(w, "Invalid format", http.StatusBadRequest, "Format must be json or csv")
+		return
+	}
+
+	report, err := h.scrapingUseCase.GetPrivacyReport(id, user.ID)
+	if err != nil {
+		log.Printf("Error getting privacy report of result %d by user %s: %v", id, user.Username, err)
+
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unauthorized") {
+			response.SendErrorResponse(w, "Result not found", http.StatusNotFound, "")
+			return
+		}
+
+		response.SendErrorResponse(w, "Failed to retrieve privacy report", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format != "csv" {
+		response.SendSuccessResponse(w, "Privacy report retrieved successfully", report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="result-%d-privacy.csv"`, id))
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"kind", "name", "category", "company", "scripts",
+		"domain", "host", "path", "session", "lifetime_seconds", "secure", "http_only", "same_site",
+	})
+	if report.ConsentPlatform != "" {
+		writer.Write([]string{"consent_platform", report.ConsentPlatform, "", "", "", "", "", "", "", "", "", "", ""})
+	}
+	for _, script := range report.ThirdPartyScripts {
+		writer.Write([]string{
+			"script", script.Host, script.Category, script.Company, strconv.Itoa(script.Scripts),
+			"", "", "", "", "", "", "", "",
+		})
+	}
+	for _, cookie := range report.Cookies {
+		writer.Write([]string{
+			"cookie", cookie.Name, "", "", "",
+			cookie.Domain, cookie.Host, cookie.Path, strconv.FormatBool(cookie.Session), strconv.FormatInt(cookie.Lifetime, 10),
+			strconv.FormatBool(cookie.Secure), strconv.FormatBool(cookie.HTTPOnly), cookie.SameSite,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing privacy report of result %d: %v", id, err)
+	}
+}
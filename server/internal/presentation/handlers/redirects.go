@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	"webscraper-v2/internal/usecase"
+)
+
+// VerifyRedirects queues the check of a redirect map sent as a text/csv
+// body, a multipart upload in the "file" field or a JSON array of
+// {"source", "expected"}. The report is read with GetRedirectReport.
+func (h *BatchHandler) VerifyRedirects(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchUpload)
+
+	mappings, err := decodeRedirectMap(r)
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid redirect map", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	batch, err := h.batchUseCase.VerifyRedirects(mappings, user.ID)
+	if err != nil {
+		log.Printf("Error verifying redirects by user %s: %v", user.Username, err)
+		response.SendErrorResponse(w, "Failed to verify redirects", http.StatusBadRequest, err.Error())
+		return
+	}
+	response.SendAcceptedResponse(w, fmt.Sprintf("Verification of %d redirects queued", batch.Total), batch)
+}
+
+// GetRedirectReport returns the report of a redirect map as JSON, with the
+// rows checked so far, or with ?format=csv as a CSV once every row is
+// checked.
+func (h *BatchHandler) GetRedirectReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	id, err := parseID(r)
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		response.SendErrorResponse(w, "Invalid format", http.StatusBadRequest, "Format must be json or csv")
+		return
+	}
+
+	report, err := h.batchUseCase.GetRedirectReport(id, user.ID)
+	if err != nil {
+		h.sendBatchError(w, id, "Failed to retrieve redirect report", err)
+		return
+	}
+
+	if format != "csv" {
+		response.SendSuccessResponse(w, fmt.Sprintf("%d of %d redirects passed", report.Passed, report.Total), report)
+		return
+	}
+	if report.Status != entity.BatchCompleted {
+		response.SendErrorResponse(w, "Redirect report not ready", http.StatusConflict,
+			fmt.Sprintf("%d of %d redirects are still pending", report.Pending, report.Total))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="redirect-report-%d.csv"`, id))
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"source", "expected", "final_url", "hops", "status_codes", "result", "reason"})
+	for _, row := range report.Rows {
+		codes := make([]string, len(row.StatusCodes))
+		for i, code := range row.StatusCodes {
+			codes[i] = strconv.Itoa(code)
+		}
+		result := "fail"
+		if row.Pass {
+			result = "pass"
+		}
+		writer.Write([]string{
+			row.Source, row.Expected, row.FinalURL, strconv.Itoa(row.Hops),
+			strings.Join(codes, " "), result, row.Reason,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing redirect report of batch %d: %v", id, err)
+	}
+}
+
+func decodeRedirectMap(r *http.Request) ([]entity.RedirectMapping, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing file: %w", err)
+		}
+		defer file.Close()
+		return usecase.ParseRedirectMap(file)
+
+	case "text/csv", "text/plain":
+		return usecase.ParseRedirectMap(r.Body)
+	}
+
+	var mappings []entity.RedirectMapping
+	if err := json.NewDecoder(r.Body).Decode(&mappings); err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
+	}
+	return mappings, nil
+}
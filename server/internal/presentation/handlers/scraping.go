@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 	"webscraper-v2/internal/presentation/middleware"
 	"webscraper-v2/internal/presentation/response"
 	"webscraper-v2/internal/usecase"
+	pkgerrors "webscraper-v2/pkg/errors"
 
 	"github.com/gorilla/mux"
 )
@@ -27,34 +30,6 @@ func NewScrapingHandler(scrapingUseCase *usecase.ScrapingUseCase, hub *SSEHub) *
 	}
 }
 
-func (h *ScrapingHandler) Scrape(w http.ResponseWriter, r *http.Request) {
-	user := middleware.GetUserFromContext(r.Context())
-
-	if user == nil {
-		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
-		return
-	}
-	var req struct {
-		URL string `json:"url"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.SendErrorResponse(w, "Invalid JSON format", http.StatusBadRequest, err.Error())
-		return
-	}
-
-	log.Printf("Scraping URL: %s", req.URL)
-	result, err := h.scrapingUseCase.ScrapeURL(r.Context(), req.URL, user.ID)
-
-	if err != nil {
-		log.Printf("Error scraping URL %s: %v", req.URL, err)
-		response.SendErrorResponse(w, "Failed to scrape URL", http.StatusInternalServerError, err.Error())
-		return
-	}
-	log.Printf("Successfully scraped URL: %s (Status: %d, Words: %d)", req.URL, result.StatusCode, result.WordCount)
-	response.SendSuccessResponse(w, "URL scraped successfully", result)
-}
-
 func (h *ScrapingHandler) PublicScrape(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		URL string `json:"url"`
@@ -77,6 +52,15 @@ func (h *ScrapingHandler) PublicScrape(w http.ResponseWriter, r *http.Request) {
 	response.SendSuccessResponse(w, "URL scraped successfully", result)
 }
 
+func (h *ScrapingHandler) GetExtractors(w http.ResponseWriter, r *http.Request) {
+	extractors := h.scrapingUseCase.Extractors()
+	response.SendSuccessResponse(w, fmt.Sprintf("Retrieved %d extractors", len(extractors)), extractors)
+}
+
+func (h *ScrapingHandler) GetProxies(w http.ResponseWriter, r *http.Request) {
+	response.SendSuccessResponse(w, "Proxy pools retrieved successfully", h.scrapingUseCase.ProxyStatus())
+}
+
 func (h *ScrapingHandler) GetResults(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
 	if user == nil {
@@ -92,9 +76,13 @@ func (h *ScrapingHandler) GetResults(w http.ResponseWriter, r *http.Request) {
 		perPage = pp
 	}
 
-	paginatedResults, err := h.scrapingUseCase.GetAllResultsPaginated(user.ID, page, perPage)
+	paginatedResults, err := h.scrapingUseCase.GetAllResultsPaginated(user.ID, page, perPage, r.URL.Query().Get("sort"))
 	if err != nil {
 		log.Printf("Error getting results: %v", err)
+		if errors.Is(err, pkgerrors.ErrInvalidInput) {
+			response.SendErrorResponse(w, "Invalid sort", http.StatusBadRequest, err.Error())
+			return
+		}
 		response.SendErrorResponse(w, "Failed to retrieve results", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -106,6 +94,33 @@ func (h *ScrapingHandler) GetResults(w http.ResponseWriter, r *http.Request) {
 	response.SendSuccessResponse(w, "Results retrieved successfully", paginatedResults)
 }
 
+// GetFailures lists failed fetch attempts, filtered by the optional code and
+// url query parameters.
+func (h *ScrapingHandler) GetFailures(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	query := r.URL.Query()
+	page, perPage := 1, 50
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(query.Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	failures, err := h.scrapingUseCase.GetFailures(user.ID, query.Get("code"), query.Get("url"), page, perPage)
+	if err != nil {
+		log.Printf("Error getting scrape failures: %v", err)
+		response.SendErrorResponse(w, "Failed to retrieve scrape failures", http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.SendSuccessResponse(w, fmt.Sprintf("Retrieved %d scrape failures", len(failures.Data)), failures)
+}
+
 func (h *ScrapingHandler) GetResult(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
 
@@ -137,6 +152,66 @@ func (h *ScrapingHandler) GetResult(w http.ResponseWriter, r *http.Request) {
 	response.SendSuccessResponse(w, "Result retrieved successfully", result)
 }
 
+// GetResultTable downloads the n-th table (0-based) of a result, as JSON by
+// default or as CSV with ?format=csv.
+func (h *ScrapingHandler) GetResultTable(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	id, err := parseID(r)
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	n, err := strconv.Atoi(mux.Vars(r)["n"])
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid table index", http.StatusBadRequest, "Table index must be a valid number")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		response.SendErrorResponse(w, "Invalid format", http.StatusBadRequest, "Format must be json or csv")
+		return
+	}
+
+	table, err := h.scrapingUseCase.GetResultTable(id, user.ID, n)
+	if err != nil {
+		log.Printf("Error getting table %d of result %d by user %s: %v", n, id, user.Username, err)
+
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unauthorized") {
+			response.SendErrorResponse(w, "Table not found", http.StatusNotFound, fmt.Sprintf("No table %d in result %d", n, id))
+			return
+		}
+
+		response.SendErrorResponse(w, "Failed to retrieve table", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format != "csv" {
+		response.SendSuccessResponse(w, "Table retrieved successfully", table)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="result-%d-table-%d.csv"`, id, n))
+	writer := csv.NewWriter(w)
+	writer.Write(table.Columns)
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, column := range table.Columns {
+			record[i] = row[column]
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing table %d of result %d: %v", n, id, err)
+	}
+}
+
 func (h *ScrapingHandler) DeleteResult(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
 	if user == nil {
@@ -166,7 +241,6 @@ func (h *ScrapingHandler) DeleteResult(w http.ResponseWriter, r *http.Request) {
 	response.SendNoContent(w)
 }
 
-
 func (h *ScrapingHandler) StreamResults(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
 	if user == nil {
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	"webscraper-v2/internal/usecase"
+)
+
+type SessionHandler struct {
+	sessionUseCase *usecase.SessionUseCase
+}
+
+func NewSessionHandler(sessionUseCase *usecase.SessionUseCase) *SessionHandler {
+	return &SessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	var req entity.CreateSessionProfileRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.SendErrorResponse(w, "Invalid JSON format", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profile, err := h.sessionUseCase.CreateProfile(&req, user.ID)
+
+	if err != nil {
+		log.Printf("Error creating session profile: %v", err)
+		response.SendErrorResponse(w, "Failed to create session profile", http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("Session profile created: %s (ID: %d) by user %s", profile.Name, profile.ID, user.Username)
+	response.SendSuccessResponse(w, "Session profile created successfully", profile)
+}
+
+func (h *SessionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	profiles, err := h.sessionUseCase.GetProfiles(user.ID)
+
+	if err != nil {
+		log.Printf("Error getting session profiles: %v", err)
+		response.SendErrorResponse(w, "Failed to retrieve session profiles", http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.SendSuccessResponse(w, fmt.Sprintf("Retrieved %d session profiles", len(profiles)), profiles)
+}
+
+func (h *SessionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	profile, err := h.sessionUseCase.GetProfile(id, user.ID)
+
+	if err != nil {
+		h.sendProfileError(w, id, "Failed to retrieve session profile", http.StatusInternalServerError, err)
+		return
+	}
+	response.SendSuccessResponse(w, "Session profile retrieved successfully", profile)
+}
+
+func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	var req entity.UpdateSessionProfileRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.SendErrorResponse(w, "Invalid JSON format", http.StatusBadRequest, err.Error())
+		return
+	}
+	profile, err := h.sessionUseCase.UpdateProfile(id, &req, user.ID)
+
+	if err != nil {
+		h.sendProfileError(w, id, "Failed to update session profile", http.StatusBadRequest, err)
+		return
+	}
+	log.Printf("Updated session profile ID: %d (%s)", id, profile.Name)
+	response.SendSuccessResponse(w, "Session profile updated successfully", profile)
+}
+
+func (h *SessionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+
+	if err := h.sessionUseCase.DeleteProfile(id, user.ID); err != nil {
+		h.sendProfileError(w, id, "Failed to delete session profile", http.StatusInternalServerError, err)
+		return
+	}
+	log.Printf("Deleted session profile ID: %d by user %s", id, user.Username)
+	response.SendNoContent(w)
+}
+
+// Test runs the profile's login flow and reports whether it succeeded.
+func (h *SessionHandler) Test(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+	id, err := parseID(r)
+
+	if err != nil {
+		response.SendErrorResponse(w, "Invalid ID format", http.StatusBadRequest, "ID must be a valid number")
+		return
+	}
+	result, err := h.sessionUseCase.TestProfile(r.Context(), id, user.ID)
+
+	if err != nil {
+		h.sendProfileError(w, id, "Failed to test session profile", http.StatusInternalServerError, err)
+		return
+	}
+	log.Printf("Tested session profile ID: %d (success: %t)", id, result.Success)
+	response.SendSuccessResponse(w, "Session profile tested", result)
+}
+
+func (h *SessionHandler) sendProfileError(w http.ResponseWriter, id int64, message string, status int, err error) {
+	log.Printf("Error with session profile %d: %v", id, err)
+
+	if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unauthorized") {
+		response.SendErrorResponse(w, "Session profile not found", http.StatusNotFound, fmt.Sprintf("No session profile found with ID %d", id))
+		return
+	}
+	response.SendErrorResponse(w, message, status, err.Error())
+}
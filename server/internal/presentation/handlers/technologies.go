@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"webscraper-v2/internal/presentation/middleware"
+	"webscraper-v2/internal/presentation/response"
+	pkgerrors "webscraper-v2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// GetTechnologyUsage lists the results on which the technology named in the
+// path was detected, with the version and confidence of each detection.
+func (h *ScrapingHandler) GetTechnologyUsage(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.SendErrorResponse(w, "Authentication required", http.StatusUnauthorized, "")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	usages, err := h.scrapingUseCase.FindByTechnology(user.ID, name)
+	if err != nil {
+		log.Printf("Error finding results using %s by user %s: %v", name, user.Username, err)
+
+		if errors.Is(err, pkgerrors.ErrInvalidInput) {
+			response.SendErrorResponse(w, "Invalid technology", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		response.SendErrorResponse(w, "Failed to find results", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.SendSuccessResponse(w, fmt.Sprintf("Found %d results using %s", len(usages), name), usages)
+}
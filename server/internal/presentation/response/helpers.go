@@ -36,6 +36,17 @@ func SendSuccessResponse(w http.ResponseWriter, message string, data interface{}
 	json.NewEncoder(w).Encode(response)
 }
 
+// SendAcceptedResponse answers 202 for work that continues in the background.
+func SendAcceptedResponse(w http.ResponseWriter, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	response := SuccessResponse{
+		Message: message,
+		Data:    data,
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
 func SendNoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
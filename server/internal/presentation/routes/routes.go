@@ -18,6 +18,9 @@ type Router struct {
 	authHandler     *handlers.AuthHandler
 	scrapingHandler *handlers.ScrapingHandler
 	scheduleHandler *handlers.ScheduleHandler
+	sessionHandler  *handlers.SessionHandler
+	jobHandler      *handlers.JobHandler
+	batchHandler    *handlers.BatchHandler
 	chatHandler     *handlers.ChatHandler
 	commonHandler   *handlers.CommonHandler
 	strictLimiter   *middleware.RateLimiter
@@ -32,6 +35,9 @@ func NewRouter(
 	authHandler *handlers.AuthHandler,
 	scrapingHandler *handlers.ScrapingHandler,
 	scheduleHandler *handlers.ScheduleHandler,
+	sessionHandler *handlers.SessionHandler,
+	jobHandler *handlers.JobHandler,
+	batchHandler *handlers.BatchHandler,
 	chatHandler *handlers.ChatHandler,
 	commonHandler *handlers.CommonHandler,
 ) *Router {
@@ -42,6 +48,9 @@ func NewRouter(
 		authHandler:     authHandler,
 		scrapingHandler: scrapingHandler,
 		scheduleHandler: scheduleHandler,
+		sessionHandler:  sessionHandler,
+		jobHandler:      jobHandler,
+		batchHandler:    batchHandler,
 		chatHandler:     chatHandler,
 		commonHandler:   commonHandler,
 		strictLimiter:   middleware.NewStrictRateLimiter(),
@@ -71,8 +80,9 @@ func (rt *Router) SetupRoutes() *mux.Router {
 	)
 	rt.setupAuthRoutes()
 	rt.setupPublicRoutes()
-	rt.setupAPIRoutes()
+	// Admin routes go first: the /api subrouter ends in a catch-all 404
 	rt.setupAdminRoutes()
+	rt.setupAPIRoutes()
 	rt.setupSPARoutes()
 
 	return rt.router
@@ -122,17 +132,45 @@ func (rt *Router) setupAPIRoutes() {
 
 	scraping := api.PathPrefix("/scrape").Subrouter()
 	scraping.Use(rt.moderateLimiter.Limit)
-	scraping.HandleFunc("", rt.scrapingHandler.Scrape).Methods("POST")
+	scraping.HandleFunc("", rt.jobHandler.Submit).Methods("POST")
+
+	batches := api.PathPrefix("/batches").Subrouter()
+	batches.Use(rt.moderateLimiter.Limit)
+	batches.HandleFunc("", rt.batchHandler.Create).Methods("POST")
+
+	redirects := api.PathPrefix("/redirects").Subrouter()
+	redirects.Use(rt.moderateLimiter.Limit)
+	redirects.HandleFunc("/verify", rt.batchHandler.VerifyRedirects).Methods("POST")
+	api.HandleFunc("/extractors", rt.scrapingHandler.GetExtractors).Methods("GET")
 
 	api.HandleFunc("/results/events", rt.scrapingHandler.StreamResults).Methods("GET")
 	api.HandleFunc("/results", rt.scrapingHandler.GetResults).Methods("GET")
 	api.HandleFunc("/results/{id:[0-9]+}", rt.scrapingHandler.GetResult).Methods("GET")
 	api.HandleFunc("/results/{id:[0-9]+}", rt.scrapingHandler.DeleteResult).Methods("DELETE")
+	api.HandleFunc("/results/{id:[0-9]+}/tables/{n:[0-9]+}", rt.scrapingHandler.GetResultTable).Methods("GET")
+	api.HandleFunc("/results/{id:[0-9]+}/privacy", rt.scrapingHandler.GetPrivacyReport).Methods("GET")
+	api.HandleFunc("/failures", rt.scrapingHandler.GetFailures).Methods("GET")
+	api.HandleFunc("/graph", rt.scrapingHandler.GetLinkGraph).Methods("GET")
+	api.HandleFunc("/duplicates", rt.scrapingHandler.GetDuplicates).Methods("GET")
+	api.HandleFunc("/technologies/{name}", rt.scrapingHandler.GetTechnologyUsage).Methods("GET")
 	api.HandleFunc("/schedules", rt.scheduleHandler.Create).Methods("POST")
 	api.HandleFunc("/schedules", rt.scheduleHandler.GetAll).Methods("GET")
 	api.HandleFunc("/schedules/{id:[0-9]+}", rt.scheduleHandler.GetByID).Methods("GET")
 	api.HandleFunc("/schedules/{id:[0-9]+}", rt.scheduleHandler.Update).Methods("PUT")
 	api.HandleFunc("/schedules/{id:[0-9]+}", rt.scheduleHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/sessions", rt.sessionHandler.Create).Methods("POST")
+	api.HandleFunc("/sessions", rt.sessionHandler.GetAll).Methods("GET")
+	api.HandleFunc("/sessions/{id:[0-9]+}", rt.sessionHandler.GetByID).Methods("GET")
+	api.HandleFunc("/sessions/{id:[0-9]+}", rt.sessionHandler.Update).Methods("PUT")
+	api.HandleFunc("/sessions/{id:[0-9]+}", rt.sessionHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/sessions/{id:[0-9]+}/test", rt.sessionHandler.Test).Methods("POST")
+	api.HandleFunc("/jobs/{id:[0-9]+}", rt.jobHandler.GetByID).Methods("GET")
+	api.HandleFunc("/jobs/{id:[0-9]+}/cancel", rt.jobHandler.Cancel).Methods("POST")
+	api.HandleFunc("/batches", rt.batchHandler.GetAll).Methods("GET")
+	api.HandleFunc("/batches/{id:[0-9]+}", rt.batchHandler.GetByID).Methods("GET")
+	api.HandleFunc("/batches/{id:[0-9]+}/items", rt.batchHandler.GetItems).Methods("GET")
+	api.HandleFunc("/batches/{id:[0-9]+}/summary", rt.batchHandler.GetSummary).Methods("GET")
+	api.HandleFunc("/redirects/verify/{id:[0-9]+}", rt.batchHandler.GetRedirectReport).Methods("GET")
 
 	api.HandleFunc("/chat/parse", rt.chatHandler.ParseMessage).Methods("POST")
 	api.HandleFunc("/chat/execute", rt.chatHandler.ExecuteAction).Methods("POST")
@@ -147,4 +185,6 @@ func (rt *Router) setupAdminRoutes() {
 	adminAPI := rt.router.PathPrefix("/api/admin").Subrouter()
 	adminAPI.Use(rt.jwtMiddleware.RequireAuth)
 	adminAPI.Use(rt.jwtMiddleware.RequireRole("admin"))
+
+	adminAPI.HandleFunc("/proxies", rt.scrapingHandler.GetProxies).Methods("GET")
 }
@@ -21,6 +21,8 @@ type Server struct {
 	router     *mux.Router
 	routerMgr  *routes.Router
 	scheduleUC *usecase.ScheduleUseCase
+	jobUC      *usecase.JobUseCase
+	batchUC    *usecase.BatchUseCase
 	httpServer *http.Server
 }
 
@@ -30,6 +32,9 @@ func NewServer(
 	scrapingUC *usecase.ScrapingUseCase,
 	authUC *usecase.AuthUseCase,
 	scheduleUC *usecase.ScheduleUseCase,
+	sessionUC *usecase.SessionUseCase,
+	jobUC *usecase.JobUseCase,
+	batchUC *usecase.BatchUseCase,
 	chatUC *usecase.ChatUseCase,
 ) *Server {
 	jwtMiddleware := middleware.NewJWTMiddleware(authUC)
@@ -40,6 +45,9 @@ func NewServer(
 	authHandler := handlers.NewAuthHandler(authUC)
 	scrapingHandler := handlers.NewScrapingHandler(scrapingUC, sseHub)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleUC)
+	sessionHandler := handlers.NewSessionHandler(sessionUC)
+	jobHandler := handlers.NewJobHandler(jobUC)
+	batchHandler := handlers.NewBatchHandler(batchUC)
 	chatHandler := handlers.NewChatHandler(chatUC, scrapingUC, scheduleUC)
 	commonHandler := handlers.NewCommonHandler(cfg)
 
@@ -49,6 +57,9 @@ func NewServer(
 		authHandler,
 		scrapingHandler,
 		scheduleHandler,
+		sessionHandler,
+		jobHandler,
+		batchHandler,
 		chatHandler,
 		commonHandler,
 	)
@@ -59,11 +70,15 @@ func NewServer(
 		router:     routerManager.SetupRoutes(),
 		routerMgr:  routerManager,
 		scheduleUC: scheduleUC,
+		jobUC:      jobUC,
+		batchUC:    batchUC,
 	}
 }
 
 func (s *Server) Start() error {
 	s.scheduleUC.StartScheduler()
+	s.jobUC.Start()
+	s.batchUC.Start()
 
 	s.logEndpoints()
 
@@ -100,16 +115,39 @@ func (s *Server) logEndpoints() {
 		"POST /api/auth/login - Login user",
 		"POST /api/auth/refresh - Refresh token",
 		"GET  /api/profile - Get user profile",
-		"POST /api/scrape - Scrape URL",
-		"GET  /api/results - Get all results",
+		"POST /api/scrape - Queue a scrape job",
+		"GET  /api/jobs/{id} - Get scrape job status",
+		"POST /api/jobs/{id}/cancel - Cancel scrape job",
+		"POST /api/batches - Scrape a list of URLs (JSON, CSV or TXT)",
+		"GET  /api/batches - Get user batches",
+		"GET  /api/batches/{id} - Get batch progress",
+		"GET  /api/batches/{id}/items - Get batch URLs (?status=)",
+		"GET  /api/batches/{id}/summary - Get batch summary",
+		"GET  /api/extractors - List registered extractors",
+		"GET  /api/results - Get all results (?sort=created_at|published_at)",
 		"GET  /api/results/{id} - Get specific result",
 		"DELETE /api/results/{id} - Delete result",
+		"GET  /api/results/{id}/tables/{n} - Download a table (?format=json|csv)",
+		"GET  /api/results/{id}/privacy - Privacy audit of a result (?format=json|csv)",
+		"GET  /api/failures - Failed scrape attempts (?code=&url=)",
+		"GET  /api/graph - Internal link graph of a site (?host=&format=json|graphml)",
+		"GET  /api/duplicates - Near-duplicate and thin pages, repeated titles, descriptions and H1s (?host=&threshold=&thin_words=)",
+		"GET  /api/technologies/{name} - Results on which a technology was detected",
+		"POST /api/redirects/verify - Verify a CSV redirect map",
+		"GET  /api/redirects/verify/{id} - Get redirect map report (?format=json|csv)",
 		"POST /api/schedules - Create schedule",
 		"GET  /api/schedules - Get user schedules",
 		"GET  /api/schedules/{id} - Get specific schedule",
 		"PUT  /api/schedules/{id} - Update schedule",
 		"DELETE /api/schedules/{id} - Delete schedule",
+		"POST /api/sessions - Create session profile",
+		"GET  /api/sessions - Get user session profiles",
+		"GET  /api/sessions/{id} - Get specific session profile",
+		"PUT  /api/sessions/{id} - Update session profile",
+		"DELETE /api/sessions/{id} - Delete session profile",
+		"POST /api/sessions/{id}/test - Test session profile login",
 		"GET  /api/admin/users - Get all users (admin only)",
+		"GET  /api/admin/proxies - Proxy pool health (admin only)",
 		"GET  /api/health - Health check",
 	}
 
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/config"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/validator"
+)
+
+// worstPagesInSummary is how many of the lowest scoring pages a batch
+// summary lists.
+const worstPagesInSummary = 10
+
+// BatchUseCase scrapes lists of URLs. All batches share one concurrency
+// limit, so running several at once does not multiply the load.
+type BatchUseCase struct {
+	batchRepo  repository.BatchRepository
+	scrapingUC *ScrapingUseCase
+	config     *config.Config
+	validator  *validator.Validator
+	// slots holds one token per scrape in flight, across batches
+	slots  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBatchUseCase(batchRepo repository.BatchRepository, scrapingUC *ScrapingUseCase, cfg *config.Config) *BatchUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BatchUseCase{
+		batchRepo:  batchRepo,
+		scrapingUC: scrapingUC,
+		config:     cfg,
+		validator:  validator.NewValidator(),
+		slots:      make(chan struct{}, cfg.Scraping.Batch.Concurrency),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// ParseURLList reads URLs from a CSV or plain text upload. CSV files use
+// the "url" column when there is a header naming it, else the first column;
+// text files hold one URL per line and may have "#" comments.
+func ParseURLList(r io.Reader, format string) ([]string, error) {
+	var urls []string
+
+	if format == "csv" {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		reader.TrimLeadingSpace = true
+
+		column, first := 0, true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV: %w", err)
+			}
+			if first {
+				first = false
+				if i := headerColumn(record, "url"); i >= 0 {
+					column = i
+					continue
+				}
+			}
+			if column < len(record) {
+				urls = append(urls, record[column])
+			}
+		}
+		return urls, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading URL list: %w", err)
+	}
+	return urls, nil
+}
+
+// headerColumn returns the index of the header named name, or -1.
+func headerColumn(record []string, name string) int {
+	for i, field := range record {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(field, "\ufeff")), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeBatchURL lower-cases scheme and host and drops the fragment, so
+// trivially different spellings of a URL are scraped once.
+func normalizeBatchURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// CreateBatch validates and deduplicates the URLs of req and starts
+// scraping them in the background.
+func (uc *BatchUseCase) CreateBatch(req *entity.CreateBatchRequest, userID int64) (*entity.Batch, error) {
+	if err := uc.scrapingUC.ValidateOptions(req.Options); err != nil {
+		return nil, err
+	}
+	if err := uc.scrapingUC.ValidateSessionProfile(req.Options, userID); err != nil {
+		return nil, err
+	}
+
+	batch := &entity.Batch{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Kind:    entity.BatchScrape,
+		Options: req.Options,
+	}
+	if err := uc.validator.ValidateMaxLength(batch.Name, "name", 100); err != nil {
+		return nil, pkgerrors.ValidationError(err.Error())
+	}
+
+	seen := make(map[string]bool, len(req.URLs))
+	items := make([]*entity.BatchItem, 0, len(req.URLs))
+	for _, raw := range req.URLs {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if err := uc.validator.ValidateURL(raw); err != nil {
+			batch.Rejected = append(batch.Rejected, entity.RejectedURL{URL: raw, Reason: err.Error()})
+			continue
+		}
+		normalized := normalizeBatchURL(raw)
+		if seen[normalized] {
+			batch.Duplicates++
+			continue
+		}
+		seen[normalized] = true
+		items = append(items, &entity.BatchItem{URL: normalized})
+	}
+
+	if len(items) == 0 {
+		return nil, pkgerrors.ValidationError("no valid URLs in the batch")
+	}
+	if max := uc.config.Scraping.Batch.MaxURLs; len(items) > max {
+		return nil, pkgerrors.ValidationError(fmt.Sprintf("a batch can have at most %d URLs, got %d", max, len(items)))
+	}
+	if batch.Name == "" {
+		batch.Name = fmt.Sprintf("Batch of %d URLs", len(items))
+	}
+
+	if err := uc.batchRepo.Create(batch, items); err != nil {
+		return nil, pkgerrors.DatabaseError("create batch", err)
+	}
+	log.Printf("✅ Batch created: %s (ID: %d) with %d URLs, %d duplicates and %d rejected",
+		batch.Name, batch.ID, len(items), batch.Duplicates, len(batch.Rejected))
+
+	uc.start(batch)
+	return batch, nil
+}
+
+func (uc *BatchUseCase) GetBatches(userID int64) ([]*entity.Batch, error) {
+	batches, err := uc.batchRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get user batches", err)
+	}
+	return batches, nil
+}
+
+func (uc *BatchUseCase) GetBatch(id, userID int64) (*entity.Batch, error) {
+	batch, err := uc.batchRepo.FindByID(id)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get batch", err)
+	}
+	if batch == nil {
+		return nil, pkgerrors.NotFoundError("batch")
+	}
+	if batch.UserID != userID {
+		return nil, pkgerrors.New(
+			pkgerrors.CodeAuthorization,
+			"unauthorized: user does not own this batch",
+			pkgerrors.ErrUnauthorized,
+		)
+	}
+	return batch, nil
+}
+
+// GetItems lists the URLs of a batch, optionally only those in status.
+func (uc *BatchUseCase) GetItems(id, userID int64, status string) ([]*entity.BatchItem, error) {
+	if _, err := uc.GetBatch(id, userID); err != nil {
+		return nil, err
+	}
+	items, err := uc.batchRepo.FindItems(id, status)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get batch items", err)
+	}
+	return items, nil
+}
+
+// GetSummary reports successes, failures by class, the average SEO score
+// and the worst scoring pages. It can be read while the batch still runs.
+func (uc *BatchUseCase) GetSummary(id, userID int64) (*entity.BatchSummary, error) {
+	if _, err := uc.GetBatch(id, userID); err != nil {
+		return nil, err
+	}
+	summary, err := uc.batchRepo.Summary(id, worstPagesInSummary)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get batch summary", err)
+	}
+	return summary, nil
+}
+
+// Start resumes the batches interrupted by the last shutdown.
+func (uc *BatchUseCase) Start() {
+	batches, err := uc.batchRepo.FindRunning()
+	if err != nil {
+		log.Printf("❌ Error loading running batches: %v", err)
+		return
+	}
+	for _, batch := range batches {
+		if err := uc.batchRepo.ResetRunningItems(batch.ID); err != nil {
+			log.Printf("❌ Error resetting batch %d: %v", batch.ID, err)
+			continue
+		}
+		log.Printf("🔄 Resuming batch %d (%d of %d URLs pending)", batch.ID, batch.Pending, batch.Total)
+		uc.start(batch)
+	}
+}
+
+// Stop interrupts the running batches and waits for them; they are resumed
+// on the next Start.
+func (uc *BatchUseCase) Stop() {
+	uc.cancel()
+	uc.wg.Wait()
+}
+
+func (uc *BatchUseCase) start(batch *entity.Batch) {
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		uc.run(batch)
+	}()
+}
+
+func (uc *BatchUseCase) run(batch *entity.Batch) {
+	items, err := uc.batchRepo.FindItems(batch.ID, entity.JobPending)
+	if err != nil {
+		log.Printf("❌ Error loading items of batch %d: %v", batch.ID, err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		select {
+		case uc.slots <- struct{}{}:
+		case <-uc.ctx.Done():
+		}
+		if uc.ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(item *entity.BatchItem) {
+			defer wg.Done()
+			defer func() { <-uc.slots }()
+			if batch.Kind == entity.BatchRedirects {
+				uc.verifyItem(item)
+			} else {
+				uc.scrapeItem(batch, item)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if uc.ctx.Err() != nil {
+		log.Printf("⚠️  Batch %d interrupted, it will resume on restart", batch.ID)
+		return
+	}
+	if err := uc.batchRepo.Finish(batch.ID); err != nil {
+		log.Printf("❌ Error finishing batch %d: %v", batch.ID, err)
+		return
+	}
+	log.Printf("✅ Batch %d finished", batch.ID)
+	uc.scrapingUC.notify(batch.UserID)
+}
+
+func (uc *BatchUseCase) scrapeItem(batch *entity.Batch, item *entity.BatchItem) {
+	item.Status = entity.JobRunning
+	if err := uc.batchRepo.UpdateItem(item); err != nil {
+		log.Printf("❌ Error updating batch item %d: %v", item.ID, err)
+	}
+
+	result, err := uc.scrapingUC.Scrape(uc.ctx, &entity.ScrapeRequest{URL: item.URL, Options: batch.Options}, batch.UserID)
+	if err != nil && errors.Is(uc.ctx.Err(), context.Canceled) {
+		// left running; reset to pending when the batch resumes
+		return
+	}
+
+	switch {
+	case err != nil:
+		item.Status = entity.JobFailed
+		item.ErrorCode = FailureCode(err)
+		item.Error = err.Error()
+	case result.StatusCode >= 400:
+		// the page was saved, but for an audit an error page is a failure
+		item.Status = entity.JobFailed
+		item.ErrorCode = classifyStatus(result.StatusCode)
+		item.Error = fmt.Sprintf("HTTP %d", result.StatusCode)
+		item.ResultID = &result.ID
+	default:
+		item.Status = entity.JobCompleted
+		item.ResultID = &result.ID
+	}
+	if err := uc.batchRepo.UpdateItem(item); err != nil {
+		log.Printf("❌ Error updating batch item %d: %v", item.ID, err)
+	}
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/simhash"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	DefaultDuplicateThreshold = 0.9
+	DefaultThinWordCount      = 200
+)
+
+// calculateSimHash fingerprints the main text of the page: the first <main>
+// or <article>, else the body without navigation, header, footer, sidebars
+// and forms.
+func (uc *ScrapingUseCase) calculateSimHash(doc *html.Node, result *entity.ScrapingResult) {
+	root := findElement(doc, "main")
+	if root == nil {
+		root = findElement(doc, "article")
+	}
+	if root == nil {
+		root = doc
+	}
+
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "head", "script", "style", "noscript", "template", "nav", "header", "footer", "aside", "form":
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	if fingerprint := simhash.Fingerprint(sb.String()); fingerprint != 0 {
+		result.SimHash = simhash.Format(fingerprint)
+	}
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// GetDuplicates reports the near-duplicate clusters, the repeated titles,
+// descriptions and H1s and the thin pages among the results of the user,
+// optionally only those of host. Only the latest result of each page counts.
+func (uc *ScrapingUseCase) GetDuplicates(userID int64, host string, threshold float64, thinWords int) (*entity.DuplicateReport, error) {
+	if threshold < 0.5 || threshold > 1 {
+		return nil, pkgerrors.ValidationError("threshold must be between 0.5 and 1")
+	}
+	if thinWords < 0 {
+		return nil, pkgerrors.ValidationError("thin_words must not be negative")
+	}
+
+	fingerprints, err := uc.repo.FindFingerprints(userID)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get result fingerprints", err)
+	}
+
+	host = graphHost(host)
+	seen := make(map[string]bool)
+	var pages []*entity.PageFingerprint
+	for _, page := range fingerprints {
+		pageURL := page.URL
+		if page.FinalURL != "" {
+			pageURL = page.FinalURL
+		}
+		if host != "" && !sameSite(pageURL, host) {
+			continue
+		}
+		key := graphKey(pageURL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		pages = append(pages, page)
+	}
+
+	report := &entity.DuplicateReport{
+		Threshold:    threshold,
+		Clusters:     nearDuplicates(pages, threshold),
+		Titles:       exactDuplicates(pages, func(p *entity.PageFingerprint) string { return p.Title }),
+		Descriptions: exactDuplicates(pages, func(p *entity.PageFingerprint) string { return p.Description }),
+		H1s:          exactDuplicates(pages, func(p *entity.PageFingerprint) string { return p.H1 }),
+		ThinPages:    []entity.DuplicatePage{},
+	}
+	for _, page := range pages {
+		if page.WordCount < thinWords {
+			report.ThinPages = append(report.ThinPages, duplicatePage(page))
+		}
+	}
+	return report, nil
+}
+
+// nearDuplicates links every pair of pages at least threshold similar and
+// returns the connected groups, largest first.
+func nearDuplicates(pages []*entity.PageFingerprint, threshold float64) []entity.DuplicateCluster {
+	var hashed []*entity.PageFingerprint
+	var hashes []uint64
+	for _, page := range pages {
+		if page.SimHash == "" {
+			continue
+		}
+		fingerprint, err := simhash.Parse(page.SimHash)
+		if err != nil {
+			continue
+		}
+		hashed = append(hashed, page)
+		hashes = append(hashes, fingerprint)
+	}
+
+	parent := make([]int, len(hashed))
+	lowest := make([]float64, len(hashed))
+	for i := range parent {
+		parent[i] = i
+		lowest[i] = 1
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			similarity := simhash.Similarity(hashes[i], hashes[j])
+			if similarity < threshold {
+				continue
+			}
+			a, b := find(i), find(j)
+			if a != b {
+				parent[b] = a
+				lowest[a] = math.Min(lowest[a], lowest[b])
+			}
+			lowest[a] = math.Min(lowest[a], similarity)
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range hashed {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+	clusters := []entity.DuplicateCluster{}
+	for root, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		cluster := entity.DuplicateCluster{Similarity: lowest[root]}
+		for _, i := range members {
+			cluster.Pages = append(cluster.Pages, duplicatePage(hashed[i]))
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if len(clusters[a].Pages) != len(clusters[b].Pages) {
+			return len(clusters[a].Pages) > len(clusters[b].Pages)
+		}
+		return clusters[a].Pages[0].ResultID > clusters[b].Pages[0].ResultID
+	})
+	return clusters
+}
+
+// exactDuplicates groups the pages by the value of field, ignoring case and
+// surrounding spaces, and keeps the values shared by several pages.
+func exactDuplicates(pages []*entity.PageFingerprint, field func(*entity.PageFingerprint) string) []entity.ExactDuplicate {
+	var order []string
+	groups := make(map[string]*entity.ExactDuplicate)
+	for _, page := range pages {
+		value := strings.TrimSpace(field(page))
+		if value == "" {
+			continue
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(value), " "))
+		group, ok := groups[key]
+		if !ok {
+			group = &entity.ExactDuplicate{Value: value}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.Pages = append(group.Pages, duplicatePage(page))
+	}
+
+	duplicates := []entity.ExactDuplicate{}
+	for _, key := range order {
+		if len(groups[key].Pages) > 1 {
+			duplicates = append(duplicates, *groups[key])
+		}
+	}
+	sort.SliceStable(duplicates, func(a, b int) bool {
+		return len(duplicates[a].Pages) > len(duplicates[b].Pages)
+	})
+	return duplicates
+}
+
+func duplicatePage(page *entity.PageFingerprint) entity.DuplicatePage {
+	pageURL := page.URL
+	if page.FinalURL != "" {
+		pageURL = page.FinalURL
+	}
+	return entity.DuplicatePage{
+		ResultID:  page.ResultID,
+		URL:       pageURL,
+		Title:     page.Title,
+		WordCount: page.WordCount,
+	}
+}
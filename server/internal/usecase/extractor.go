@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+// Extractor analyses a parsed page and writes its findings through the
+// ResultBuilder. Built-in analyses and in-house plugins implement it alike.
+type Extractor interface {
+	Extract(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error
+}
+
+// ExtractorFunc adapts an ordinary function to the Extractor interface.
+type ExtractorFunc func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error
+
+func (f ExtractorFunc) Extract(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+	return f(ctx, doc, page, b)
+}
+
+// PageResponse is the response metadata handed to every extractor.
+type PageResponse struct {
+	URL        string
+	FinalURL   string
+	StatusCode int
+	Header     http.Header
+	Options    entity.ScrapeOptions
+	// Client shares the page's transport (and proxy) for follow-up requests.
+	Client *http.Client
+
+	credentials *entity.RequestCredentials
+}
+
+// NewRequest builds a follow-up request with the scrape's user agent. The
+// scrape's credentials are only attached when target is on the host they
+// were given for, which is not the page's host after a cross-host redirect.
+func (p *PageResponse) NewRequest(ctx context.Context, method, target string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", p.Options.UserAgent)
+	if sameHost(p.URL, req.URL.String()) {
+		applyCredentials(req, p.credentials)
+	}
+	return req, nil
+}
+
+// ResultBuilder gives extractors access to the result under construction.
+// Plugins should keep their output under their own extension key.
+type ResultBuilder struct {
+	result *entity.ScrapingResult
+}
+
+func newResultBuilder(result *entity.ScrapingResult) *ResultBuilder {
+	return &ResultBuilder{result: result}
+}
+
+func (b *ResultBuilder) Result() *entity.ScrapingResult {
+	return b.result
+}
+
+// SetExtension stores value, JSON encoded, under name in the result's
+// extensions map.
+func (b *ResultBuilder) SetExtension(name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error marshaling extension %s: %w", name, err)
+	}
+	if b.result.Extensions == nil {
+		b.result.Extensions = make(map[string]json.RawMessage)
+	}
+	b.result.Extensions[name] = data
+	return nil
+}
+
+func (b *ResultBuilder) Extension(name string) (json.RawMessage, bool) {
+	data, ok := b.result.Extensions[name]
+	return data, ok
+}
+
+// ExtractorRegistration describes an extractor in the registry. Order breaks
+// ties between extractors whose dependencies are satisfied (lower runs
+// first); Enabled is used when a request does not say otherwise.
+type ExtractorRegistration struct {
+	Name      string    `json:"name"`
+	Extractor Extractor `json:"-"`
+	DependsOn []string  `json:"depends_on"`
+	Order     int       `json:"order"`
+	Enabled   bool      `json:"enabled"`
+}
+
+type ExtractorRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]ExtractorRegistration
+}
+
+func NewExtractorRegistry() *ExtractorRegistry {
+	return &ExtractorRegistry{entries: make(map[string]ExtractorRegistration)}
+}
+
+func (r *ExtractorRegistry) Register(reg ExtractorRegistration) error {
+	if reg.Name == "" {
+		return fmt.Errorf("extractor name is required")
+	}
+	if reg.Extractor == nil {
+		return fmt.Errorf("extractor %s has no implementation", reg.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.entries[reg.Name]; exists {
+		return fmt.Errorf("extractor %s is already registered", reg.Name)
+	}
+	r.entries[reg.Name] = reg
+	return nil
+}
+
+func (r *ExtractorRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.entries[name]
+	return ok
+}
+
+// List returns every registration sorted by order and name.
+func (r *ExtractorRegistry) List() []ExtractorRegistration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]ExtractorRegistration, 0, len(r.entries))
+	for _, reg := range r.entries {
+		list = append(list, reg)
+	}
+	sortRegistrations(list)
+	return list
+}
+
+// Plan returns the extractors to run, dependencies first. enabled decides
+// whether a registered extractor was requested; the dependencies of a
+// requested extractor always run.
+func (r *ExtractorRegistry) Plan(enabled func(reg ExtractorRegistration) bool) ([]ExtractorRegistration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	selected := make(map[string]bool)
+	var include func(name string, path []string) error
+	include = func(name string, path []string) error {
+		reg, ok := r.entries[name]
+		if !ok {
+			return fmt.Errorf("extractor %s depends on unknown extractor %s", path[len(path)-1], name)
+		}
+		if selected[name] {
+			return nil
+		}
+		selected[name] = true
+		for _, dep := range reg.DependsOn {
+			if err := include(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for name, reg := range r.entries {
+		if enabled(reg) {
+			if err := include(name, []string{name}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Kahn's algorithm; the ready set is kept sorted so the plan is stable
+	pending := make(map[string]int, len(selected))
+	dependents := make(map[string][]string)
+	for name := range selected {
+		deps := r.entries[name].DependsOn
+		pending[name] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []ExtractorRegistration
+	for name, count := range pending {
+		if count == 0 {
+			ready = append(ready, r.entries[name])
+		}
+	}
+
+	plan := make([]ExtractorRegistration, 0, len(selected))
+	for len(ready) > 0 {
+		sortRegistrations(ready)
+		next := ready[0]
+		ready = ready[1:]
+		plan = append(plan, next)
+		for _, dependent := range dependents[next.Name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, r.entries[dependent])
+			}
+		}
+	}
+
+	if len(plan) != len(selected) {
+		return nil, fmt.Errorf("extractor dependencies contain a cycle")
+	}
+	return plan, nil
+}
+
+func sortRegistrations(list []ExtractorRegistration) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Order != list[j].Order {
+			return list[i].Order < list[j].Order
+		}
+		return list[i].Name < list[j].Name
+	})
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/infrastructure/proxy"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/netguard"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ProxyDirect is the proxy option value that bypasses the default pool.
+const ProxyDirect = "direct"
+
+// proxySelection is the outbound proxy picked for one scrape. Both fields are
+// nil for direct connections.
+type proxySelection struct {
+	pool  *proxy.Pool
+	proxy *proxy.Proxy
+}
+
+func (s proxySelection) reportSuccess() {
+	if s.proxy != nil {
+		s.pool.ReportSuccess(s.proxy)
+	}
+}
+
+// reportFailure counts err against the proxy when the proxy is to blame.
+func (s proxySelection) reportFailure(err error) {
+	if s.proxy != nil && isProxyFailure(err) {
+		s.pool.ReportFailure(s.proxy, err)
+	}
+}
+
+// errProxyRefused is returned when the proxy answers a CONNECT with an
+// error of its own rather than one about the target.
+var errProxyRefused = errors.New("proxy refused the tunnel")
+
+// isProxyFailure tells the errors that are the proxy's fault: failing to
+// dial it or being refused a tunnel. Blocked destinations, DNS and TLS
+// errors of the target and timeouts of a slow site say nothing about the
+// proxy.
+func isProxyFailure(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return true
+	}
+	return errors.Is(err, errProxyRefused)
+}
+
+// checkProxyConnect flags CONNECT answers that blame the proxy. 502 and 504
+// mean the proxy could not reach the target, which is the target's fault.
+func checkProxyConnect(ctx context.Context, proxyURL *url.URL, req *http.Request, resp *http.Response) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusGatewayTimeout:
+		return fmt.Errorf("proxy could not reach %s: %s", req.Host, resp.Status)
+	}
+	return fmt.Errorf("%w: %s", errProxyRefused, resp.Status)
+}
+
+func (s proxySelection) redacted() string {
+	if s.proxy == nil {
+		return ""
+	}
+	return s.proxy.Redacted()
+}
+
+func (uc *ScrapingUseCase) selectProxy(opts entity.ScrapeOptions) (proxySelection, error) {
+	name := opts.Proxy
+	if name == "" && uc.proxies != nil {
+		name = uc.proxies.DefaultPool()
+	}
+	if name == "" || name == ProxyDirect {
+		return proxySelection{}, nil
+	}
+	if uc.proxies == nil {
+		return proxySelection{}, fmt.Errorf("proxy pool %s is not configured", name)
+	}
+
+	pool, ok := uc.proxies.Pool(name)
+	if !ok {
+		return proxySelection{}, fmt.Errorf("proxy pool %s is not configured", name)
+	}
+	px, err := pool.Pick()
+	if err != nil {
+		return proxySelection{}, err
+	}
+	return proxySelection{pool: pool, proxy: px}, nil
+}
+
+// transportFor returns a shared transport per proxy so connections are
+// reused across scrapes going through the same egress point. Every
+// transport goes through the network guard: direct connections are checked
+// when dialing, proxied requests before they are sent to the proxy.
+func (uc *ScrapingUseCase) transportFor(sel proxySelection) http.RoundTripper {
+	key := ""
+	if sel.proxy != nil {
+		key = sel.proxy.URL.String()
+	}
+	if t, ok := uc.transports.Load(key); ok {
+		return t.(http.RoundTripper)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	var rt http.RoundTripper = transport
+	if sel.proxy != nil {
+		transport.Proxy = http.ProxyURL(sel.proxy.URL)
+		transport.OnProxyConnectResponse = checkProxyConnect
+		if uc.guard != nil {
+			rt = uc.guard.RoundTripper(transport)
+		}
+	} else {
+		// direct means direct: outbound proxies are configured in scraping.proxy
+		transport.Proxy = nil
+		if uc.guard != nil {
+			transport.DialContext = uc.guard.DialContext(&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			})
+		}
+	}
+	actual, _ := uc.transports.LoadOrStore(key, rt)
+	return actual.(http.RoundTripper)
+}
+
+// checkDestination rejects targets that resolve to blocked addresses before
+// any request is made, so users get a validation error instead of a failed
+// dial. The dial-time check still applies to redirects and probes.
+func (uc *ScrapingUseCase) checkDestination(ctx context.Context, targetURL string) error {
+	if uc.guard == nil {
+		return nil
+	}
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return pkgerrors.ValidationError("invalid URL")
+	}
+	if err := uc.guard.CheckHost(ctx, u.Hostname()); err != nil {
+		if errors.Is(err, netguard.ErrBlocked) {
+			return pkgerrors.New(pkgerrors.CodeValidation, "URL not allowed", err)
+		}
+		return pkgerrors.InternalError("failed to resolve host", err)
+	}
+	return nil
+}
+
+// probeClient is used by extractors for follow-up requests (favicons,
+// manifests) so they leave through the same proxy, and with the same
+// session cookies, as the page itself. On a redirect to another host the
+// credentials of creds are dropped: Go keeps custom headers, and keeps
+// Authorization and Cookie when only the port changes.
+func (uc *ScrapingUseCase) probeClient(transport http.RoundTripper, jar http.CookieJar, creds *entity.RequestCredentials) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Jar:       jar,
+		Timeout:   5 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if creds != nil && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+				for name := range creds.Headers {
+					req.Header.Del(name)
+				}
+				req.Header.Del("Authorization")
+				// cookies of the jar are added after this
+				req.Header.Del("Cookie")
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateCredentials checks the custom headers, cookies and authentication
+// of a scrape request or schedule.
+func (uc *ScrapingUseCase) ValidateCredentials(creds *entity.RequestCredentials) error {
+	if creds == nil {
+		return nil
+	}
+	for name, value := range creds.Headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return pkgerrors.ValidationError(fmt.Sprintf("invalid header: %s", name))
+		}
+		switch strings.ToLower(name) {
+		case "host", "content-length", "transfer-encoding", "connection":
+			return pkgerrors.ValidationError(fmt.Sprintf("header %s cannot be overridden", name))
+		}
+	}
+	for _, cookie := range creds.Cookies {
+		if cookie.Name == "" || !httpguts.ValidHeaderFieldName(cookie.Name) {
+			return pkgerrors.ValidationError(fmt.Sprintf("invalid cookie name: %q", cookie.Name))
+		}
+	}
+	if creds.Auth != nil {
+		switch creds.Auth.Type {
+		case "basic":
+			if creds.Auth.Username == "" {
+				return pkgerrors.ValidationError("basic auth requires a username")
+			}
+		case "bearer":
+			if creds.Auth.Token == "" {
+				return pkgerrors.ValidationError("bearer auth requires a token")
+			}
+		default:
+			return pkgerrors.ValidationError("auth type must be basic or bearer")
+		}
+	}
+	return nil
+}
+
+func applyCredentials(req *http.Request, creds *entity.RequestCredentials) {
+	if creds == nil {
+		return
+	}
+	for name, value := range creds.Headers {
+		req.Header.Set(name, value)
+	}
+	for _, cookie := range creds.Cookies {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	if creds.Auth != nil {
+		switch creds.Auth.Type {
+		case "basic":
+			req.SetBasicAuth(creds.Auth.Username, creds.Auth.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+creds.Auth.Token)
+		}
+	}
+}
+
+// ProxyStatus reports the health of the configured proxy pools.
+func (uc *ScrapingUseCase) ProxyStatus() []proxy.PoolStatus {
+	if uc.proxies == nil {
+		return []proxy.PoolStatus{}
+	}
+	return uc.proxies.Status()
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+)
+
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 100
+	pageRankTolerance  = 1e-9
+)
+
+// GetLinkGraph builds the internal link graph of host from the results of
+// the user. Only the latest result of each page is used, and only links
+// between scraped pages become edges.
+func (uc *ScrapingUseCase) GetLinkGraph(userID int64, host string) (*entity.LinkGraph, error) {
+	host = graphHost(host)
+	if host == "" {
+		return nil, pkgerrors.ValidationError("host is required")
+	}
+
+	results, err := uc.repo.FindAllByUserID(userID)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get user results", err)
+	}
+
+	graph := &entity.LinkGraph{
+		Host:     host,
+		Nodes:    []entity.GraphNode{},
+		Edges:    []entity.GraphEdge{},
+		Orphans:  []string{},
+		DeadEnds: []string{},
+	}
+
+	// results come newest first, so the first one of each page wins
+	index := make(map[string]int)
+	var pages []*entity.ScrapingResult
+	for _, result := range results {
+		page := result.URL
+		if result.FinalURL != "" {
+			page = result.FinalURL
+		}
+		page = normalizeBatchURL(page)
+		if !sameSite(page, host) {
+			continue
+		}
+		key := graphKey(page)
+		if _, ok := index[key]; ok {
+			continue
+		}
+		index[key] = len(pages)
+		// a redirected page is also reachable through its original URL
+		if alias := graphKey(result.URL); alias != key {
+			if _, ok := index[alias]; !ok {
+				index[alias] = len(pages)
+			}
+		}
+		pages = append(pages, result)
+		graph.Nodes = append(graph.Nodes, entity.GraphNode{
+			URL:        page,
+			ResultID:   result.ID,
+			Title:      result.Title,
+			StatusCode: result.StatusCode,
+			Depth:      -1,
+		})
+	}
+	if len(pages) == 0 {
+		return graph, nil
+	}
+
+	out := make([][]int, len(pages))
+	for i, result := range pages {
+		// results saved before InternalLinks existed only have the capped
+		// Links
+		links := result.InternalLinks
+		if links == nil {
+			links = result.Links
+		}
+		seen := make(map[int]bool)
+		for _, link := range links {
+			// only followable links are edges; hints, frames and assets are not
+			if !link.IsHyperlink() || !sameSite(link.URL, host) {
+				continue
+			}
+			graph.Nodes[i].Outlinks++
+			j, ok := index[graphKey(link.URL)]
+			if !ok || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			out[i] = append(out[i], j)
+			graph.Nodes[j].Inlinks++
+			graph.Edges = append(graph.Edges, entity.GraphEdge{
+				Source: graph.Nodes[i].URL,
+				Target: graph.Nodes[j].URL,
+				Anchor: strings.TrimSpace(link.AnchorText),
+			})
+		}
+	}
+
+	root := -1
+	for i, node := range graph.Nodes {
+		if u, err := url.Parse(node.URL); err == nil && u.Path == "/" && u.RawQuery == "" {
+			root = i
+			break
+		}
+	}
+	if root >= 0 {
+		graph.Root = graph.Nodes[root].URL
+		clickDepth(graph.Nodes, out, root)
+	}
+
+	for i, rank := range pageRank(out) {
+		node := &graph.Nodes[i]
+		node.PageRank = rank
+		node.Orphan = node.Inlinks == 0 && i != root
+		node.DeadEnd = node.Outlinks == 0
+		if node.Orphan {
+			graph.Orphans = append(graph.Orphans, node.URL)
+		}
+		if node.DeadEnd {
+			graph.DeadEnds = append(graph.DeadEnds, node.URL)
+		}
+	}
+
+	sort.SliceStable(graph.Nodes, func(a, b int) bool {
+		return graph.Nodes[a].PageRank > graph.Nodes[b].PageRank
+	})
+	return graph, nil
+}
+
+// clickDepth sets the depth of every node reachable from root, breadth
+// first.
+func clickDepth(nodes []entity.GraphNode, out [][]int, root int) {
+	nodes[root].Depth = 0
+	queue := []int{root}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range out[i] {
+			if nodes[j].Depth < 0 {
+				nodes[j].Depth = nodes[i].Depth + 1
+				queue = append(queue, j)
+			}
+		}
+	}
+}
+
+// pageRank runs the power iteration over the adjacency list out. The rank
+// of pages without links is spread over every page, so ranks sum to 1.
+func pageRank(out [][]int) []float64 {
+	n := float64(len(out))
+	rank := make([]float64, len(out))
+	for i := range rank {
+		rank[i] = 1 / n
+	}
+
+	next := make([]float64, len(out))
+	for iter := 0; iter < pageRankIterations; iter++ {
+		dangling := 0.0
+		for i, links := range out {
+			if len(links) == 0 {
+				dangling += rank[i]
+			}
+		}
+		base := (1-pageRankDamping)/n + pageRankDamping*dangling/n
+		for i := range next {
+			next[i] = base
+		}
+		for i, links := range out {
+			if len(links) == 0 {
+				continue
+			}
+			share := pageRankDamping * rank[i] / float64(len(links))
+			for _, j := range links {
+				next[j] += share
+			}
+		}
+
+		delta := 0.0
+		for i := range rank {
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// graphKey identifies a page in the graph: the normalized URL without
+// "www.", so both spellings of the host are the same page.
+func graphKey(rawURL string) string {
+	u, err := url.Parse(normalizeBatchURL(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Host = strings.TrimPrefix(u.Host, "www.")
+	return u.String()
+}
+
+// graphHost accepts a host or a full URL and returns the lower-cased host
+// without "www.".
+func graphHost(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Host
+		}
+	}
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// sameSite reports whether rawURL is on host, with or without "www.".
+func sameSite(rawURL, host string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return graphHost(u.Host) == host
+}
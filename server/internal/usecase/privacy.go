@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/pkg/trackers"
+
+	"golang.org/x/net/html"
+)
+
+// auditPrivacy lists the third-party script hosts of the page, classified
+// against the bundled tracker list, the cookies set by the redirect hops and
+// the response and the consent management platform found, if any. It only
+// sees the first load: what the page sets once the visitor consents is out
+// of its reach.
+func (uc *ScrapingUseCase) auditPrivacy(doc *html.Node, result *entity.ScrapingResult, page *PageResponse) {
+	result.ThirdPartyScripts = []entity.ThirdPartyScript{}
+	result.Cookies = []entity.Cookie{}
+
+	base, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return
+	}
+	site := registrableDomain(base.Hostname())
+
+	index := make(map[string]int)
+	uc.traverseNode(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode || node.Data != "script" {
+			return
+		}
+		src := strings.TrimSpace(htmlAttr(node, "src"))
+		if src == "" {
+			return
+		}
+		resolved, err := base.Parse(src)
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return
+		}
+		host := strings.ToLower(resolved.Hostname())
+		if result.ConsentPlatform == "" {
+			if platform, ok := trackers.ConsentPlatformByHost(host); ok {
+				result.ConsentPlatform = platform
+			}
+		}
+		if registrableDomain(host) == site {
+			return
+		}
+
+		if i, ok := index[host]; ok {
+			result.ThirdPartyScripts[i].Scripts++
+			return
+		}
+		script := entity.ThirdPartyScript{Host: host, Scripts: 1}
+		if tracker, ok := trackers.Lookup(host); ok {
+			script.Category = tracker.Category
+			script.Company = tracker.Company
+		}
+		index[host] = len(result.ThirdPartyScripts)
+		result.ThirdPartyScripts = append(result.ThirdPartyScripts, script)
+	})
+
+	now := time.Now()
+	if date, err := http.ParseTime(page.Header.Get("Date")); err == nil {
+		now = date
+	}
+	// a cookie set again further down the chain keeps its last attributes
+	type setCookie struct {
+		host   string
+		cookie *http.Cookie
+	}
+	var cookies []setCookie
+	collect := func(rawURL string, header http.Header) {
+		host := ""
+		if u, err := url.Parse(rawURL); err == nil {
+			host = strings.ToLower(u.Hostname())
+		}
+		for _, cookie := range (&http.Response{Header: header}).Cookies() {
+			cookies = append(cookies, setCookie{host: host, cookie: cookie})
+		}
+	}
+	for _, hop := range result.RedirectHops {
+		collect(hop.URL, http.Header{"Set-Cookie": hop.SetCookie})
+	}
+	collect(page.FinalURL, page.Header)
+
+	cookieIndex := make(map[string]int)
+	for _, set := range cookies {
+		cookie := set.cookie
+		record := cookieRecord(cookie, now)
+		// host-only cookies of different hosts are different cookies
+		if record.Domain == "" {
+			record.Host = set.host
+		}
+		key := record.Name + ";" + record.Domain + ";" + record.Host + ";" + record.Path
+		if i, ok := cookieIndex[key]; ok {
+			result.Cookies[i] = record
+			continue
+		}
+		cookieIndex[key] = len(result.Cookies)
+		result.Cookies = append(result.Cookies, record)
+		if result.ConsentPlatform == "" {
+			if platform, ok := trackers.ConsentPlatformByCookie(cookie.Name); ok {
+				result.ConsentPlatform = platform
+			}
+		}
+	}
+}
+
+// cookieRecord describes a Set-Cookie without its value. The lifetime comes
+// from Max-Age, which takes precedence, or from Expires relative to the
+// Date of the response.
+func cookieRecord(cookie *http.Cookie, now time.Time) entity.Cookie {
+	record := entity.Cookie{
+		Name:     cookie.Name,
+		Domain:   strings.TrimPrefix(strings.ToLower(cookie.Domain), "."),
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HTTPOnly: cookie.HttpOnly,
+	}
+	switch cookie.SameSite {
+	case http.SameSiteLaxMode:
+		record.SameSite = "Lax"
+	case http.SameSiteStrictMode:
+		record.SameSite = "Strict"
+	case http.SameSiteNoneMode:
+		record.SameSite = "None"
+	}
+
+	switch {
+	case cookie.MaxAge > 0:
+		record.Lifetime = int64(cookie.MaxAge)
+	case cookie.MaxAge < 0:
+		// Max-Age=0 or negative: the cookie is deleted
+		record.Lifetime = -1
+	case !cookie.Expires.IsZero():
+		record.Lifetime = int64(cookie.Expires.Sub(now).Seconds())
+		if record.Lifetime <= 0 {
+			record.Lifetime = -1
+		}
+	default:
+		record.Session = true
+	}
+	return record
+}
+
+// GetPrivacyReport returns the privacy audit of a result of the user.
+func (uc *ScrapingUseCase) GetPrivacyReport(id, userID int64) (*entity.PrivacyReport, error) {
+	result, err := uc.GetResult(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PrivacyReport{
+		ResultID:          result.ID,
+		URL:               result.URL,
+		FinalURL:          result.FinalURL,
+		ThirdPartyScripts: result.ThirdPartyScripts,
+		Cookies:           result.Cookies,
+		ConsentPlatform:   result.ConsentPlatform,
+	}, nil
+}
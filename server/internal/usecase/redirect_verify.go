@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+)
+
+// ParseRedirectMap reads source,expected pairs from a CSV. Columns named
+// source/from/old and expected/target/to/new are used when there is a
+// header; otherwise the first two columns.
+func ParseRedirectMap(r io.Reader) ([]entity.RedirectMapping, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var mappings []entity.RedirectMapping
+	source, expected, first := 0, 1, true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if first {
+			first = false
+			s := firstColumn(record, "source", "from", "old", "old_url")
+			e := firstColumn(record, "expected", "target", "to", "new", "new_url")
+			if s >= 0 && e >= 0 {
+				source, expected = s, e
+				continue
+			}
+		}
+		if source >= len(record) || expected >= len(record) {
+			continue
+		}
+		mappings = append(mappings, entity.RedirectMapping{
+			Source:   strings.TrimSpace(record[source]),
+			Expected: strings.TrimSpace(record[expected]),
+		})
+	}
+	return mappings, nil
+}
+
+// firstColumn returns the index of the first header matching one of names,
+// or -1.
+func firstColumn(record []string, names ...string) int {
+	for _, name := range names {
+		if i := headerColumn(record, name); i >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// VerifyRedirects starts a redirect batch that follows the source of every
+// mapping with the scraper's HTTP stack and checks that it ends, with a
+// 2xx, on the expected URL. Nothing is saved but the outcome of each row;
+// the report is read with GetRedirectReport.
+func (uc *BatchUseCase) VerifyRedirects(mappings []entity.RedirectMapping, userID int64) (*entity.Batch, error) {
+	if len(mappings) == 0 {
+		return nil, pkgerrors.ValidationError("no redirect mappings in the request")
+	}
+	if max := uc.config.Scraping.Batch.MaxURLs; len(mappings) > max {
+		return nil, pkgerrors.ValidationError(fmt.Sprintf("at most %d redirect mappings can be verified at once, got %d", max, len(mappings)))
+	}
+
+	batch := &entity.Batch{
+		UserID: userID,
+		Name:   fmt.Sprintf("Redirect map of %d URLs", len(mappings)),
+		Kind:   entity.BatchRedirects,
+	}
+	items := make([]*entity.BatchItem, len(mappings))
+	for i, mapping := range mappings {
+		items[i] = &entity.BatchItem{URL: mapping.Source, Expected: mapping.Expected}
+	}
+	if err := uc.batchRepo.Create(batch, items); err != nil {
+		return nil, pkgerrors.DatabaseError("create batch", err)
+	}
+	log.Printf("✅ Redirect batch created (ID: %d) with %d mappings", batch.ID, len(items))
+
+	uc.start(batch)
+	return batch, nil
+}
+
+// GetRedirectReport reports the rows of a redirect batch checked so far.
+func (uc *BatchUseCase) GetRedirectReport(id, userID int64) (*entity.RedirectReport, error) {
+	batch, err := uc.GetBatch(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Kind != entity.BatchRedirects {
+		return nil, pkgerrors.NotFoundError("redirect batch")
+	}
+	items, err := uc.batchRepo.FindItems(id, "")
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get batch items", err)
+	}
+
+	report := &entity.RedirectReport{
+		BatchID: batch.ID,
+		Status:  batch.Status,
+		Total:   batch.Total,
+		Passed:  batch.Succeeded,
+		Failed:  batch.Failed,
+		Pending: batch.Pending,
+		Rows:    []entity.RedirectCheck{},
+	}
+	for _, item := range items {
+		if item.Redirect != nil {
+			report.Rows = append(report.Rows, *item.Redirect)
+		}
+	}
+	return report, nil
+}
+
+func (uc *BatchUseCase) verifyItem(item *entity.BatchItem) {
+	item.Status = entity.JobRunning
+	if err := uc.batchRepo.UpdateItem(item); err != nil {
+		log.Printf("❌ Error updating batch item %d: %v", item.ID, err)
+	}
+
+	check := uc.scrapingUC.verifyRedirect(uc.ctx, entity.RedirectMapping{Source: item.URL, Expected: item.Expected})
+	if uc.ctx.Err() != nil {
+		// left running; reset to pending when the batch resumes
+		return
+	}
+
+	item.Redirect = &check
+	if check.Pass {
+		item.Status = entity.JobCompleted
+	} else {
+		item.Status = entity.JobFailed
+		item.ErrorCode = entity.FailureRedirectMismatch
+		item.Error = check.Reason
+	}
+	if err := uc.batchRepo.UpdateItem(item); err != nil {
+		log.Printf("❌ Error updating batch item %d: %v", item.ID, err)
+	}
+}
+
+func (uc *ScrapingUseCase) verifyRedirect(ctx context.Context, mapping entity.RedirectMapping) entity.RedirectCheck {
+	check := entity.RedirectCheck{
+		Source:      mapping.Source,
+		Expected:    mapping.Expected,
+		StatusCodes: []int{},
+	}
+	if err := uc.validator.ValidateURL(mapping.Source); err != nil {
+		check.Reason = "invalid source URL: " + err.Error()
+		return check
+	}
+	// the expected URL may be relative to the source
+	expected, err := url.Parse(mapping.Source)
+	if err == nil {
+		expected, err = expected.Parse(mapping.Expected)
+	}
+	if err != nil || mapping.Expected == "" {
+		check.Reason = "invalid expected URL"
+		return check
+	}
+	if err := uc.checkDestination(ctx, mapping.Source); err != nil {
+		check.Reason = err.Error()
+		return check
+	}
+
+	opts := uc.DefaultOptions()
+	opts.FollowRedirects = true
+	sel, err := uc.selectProxy(opts)
+	if err != nil {
+		check.Reason = err.Error()
+		return check
+	}
+	resp, hops, err := uc.fetchPage(ctx, mapping.Source, opts, nil, uc.transportFor(sel), nil)
+	if err != nil {
+		sel.reportFailure(err)
+		check.Reason = fmt.Sprintf("fetch failed (%s): %v", classifyFetchError(err), err)
+		return check
+	}
+	resp.Body.Close()
+	sel.reportSuccess()
+
+	check.FinalURL = resp.Request.URL.String()
+	check.Hops = len(hops)
+	for _, hop := range hops {
+		check.StatusCodes = append(check.StatusCodes, hop.StatusCode)
+	}
+	for _, issue := range auditRedirects(hops, 0) {
+		if issue.Code == entity.RedirectLoop {
+			// the response is the last hop, already listed
+			check.Reason = issue.Message
+			return check
+		}
+	}
+	check.StatusCodes = append(check.StatusCodes, resp.StatusCode)
+
+	switch {
+	case normalizeBatchURL(check.FinalURL) != normalizeBatchURL(expected.String()):
+		check.Reason = "ends on " + check.FinalURL
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		check.Reason = fmt.Sprintf("final status %d", resp.StatusCode)
+	default:
+		check.Pass = true
+	}
+	return check
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+)
+
+// auditRedirects flags loops, chains of more than longChain hops, protocol
+// downgrades and flip-flops, and temporary redirects doing the job of a
+// permanent one.
+func auditRedirects(hops []entity.RedirectHop, longChain int) []entity.RedirectIssue {
+	issues := []entity.RedirectIssue{}
+	if len(hops) == 0 {
+		return issues
+	}
+
+	last := hops[len(hops)-1]
+	for _, hop := range hops {
+		if hop.URL == last.Location {
+			issues = append(issues, entity.RedirectIssue{
+				Code:    entity.RedirectLoop,
+				Message: fmt.Sprintf("%s redirects back to %s", last.URL, last.Location),
+				Hop:     len(hops) - 1,
+			})
+			break
+		}
+	}
+
+	if longChain > 0 && len(hops) > longChain {
+		issues = append(issues, entity.RedirectIssue{
+			Code:    entity.RedirectLongChain,
+			Message: fmt.Sprintf("%d redirects before the final page, more than %d", len(hops), longChain),
+			Hop:     -1,
+		})
+	}
+
+	upgraded, flipFlop := false, false
+	for i, hop := range hops {
+		if !hop.CrossScheme {
+			continue
+		}
+		from, to := schemeOf(hop.URL), schemeOf(hop.Location)
+		switch {
+		case from == "http" && to == "https":
+			upgraded = true
+		case from == "https" && to == "http":
+			issues = append(issues, entity.RedirectIssue{
+				Code:    entity.RedirectHTTPSDowngrade,
+				Message: fmt.Sprintf("%s redirects from HTTPS to HTTP", hop.URL),
+				Hop:     i,
+			})
+			if upgraded && !flipFlop {
+				flipFlop = true
+				issues = append(issues, entity.RedirectIssue{
+					Code:    entity.RedirectSchemeFlipFlop,
+					Message: "the chain goes from HTTP to HTTPS and back to HTTP",
+					Hop:     i,
+				})
+			}
+		}
+	}
+
+	for i, hop := range hops {
+		if hop.StatusCode == http.StatusMovedPermanently || hop.StatusCode == http.StatusPermanentRedirect {
+			continue
+		}
+		if hop.Type != "" && hop.Type != entity.RedirectHTTP {
+			// client-side redirects are reported as SEO issues
+			continue
+		}
+		if isCanonicalization(hop.URL, hop.Location) {
+			issues = append(issues, entity.RedirectIssue{
+				Code: entity.RedirectTemporaryAsPermanent,
+				Message: fmt.Sprintf("%d redirect normalizes %s to %s; use 301 or 308",
+					hop.StatusCode, hop.URL, hop.Location),
+				Hop: i,
+			})
+		}
+	}
+	return issues
+}
+
+// isCanonicalization reports whether to is the same page as from with only
+// the protocol, the "www." prefix, the case of the host or a trailing slash
+// changed: redirects that are permanent by nature.
+func isCanonicalization(from, to string) bool {
+	a, errA := url.Parse(from)
+	b, errB := url.Parse(to)
+	if errA != nil || errB != nil {
+		return false
+	}
+	host := func(u *url.URL) string {
+		return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	path := func(u *url.URL) string {
+		return strings.TrimSuffix(u.EscapedPath(), "/")
+	}
+	return host(a) == host(b) && path(a) == path(b) && a.RawQuery == b.RawQuery
+}
+
+func schemeOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return strings.ToLower(u.Scheme)
+	}
+	return ""
+}
+
+func sameHost(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	return errA == nil && errB == nil && strings.EqualFold(ua.Host, ub.Host)
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/netguard"
+)
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// fetchError is a fetch that failed for good, after any retries.
+type fetchError struct {
+	Code string
+	Err  error
+}
+
+func (e *fetchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.Err
+}
+
+// fetchFailure converts a fetch error into the error returned to the caller,
+// keeping the failure code in the message.
+func fetchFailure(err error) error {
+	var fe *fetchError
+	if errors.As(err, &fe) {
+		if fe.Code == entity.FailureBlocked {
+			return pkgerrors.New(pkgerrors.CodeValidation, "URL not allowed", fe)
+		}
+		return pkgerrors.InternalError(fmt.Sprintf("failed to fetch URL (%s)", fe.Code), fe)
+	}
+	return pkgerrors.InternalError("failed to fetch URL", err)
+}
+
+// FailureCode returns the failure code of an error returned by Scrape, or
+// FailureInternal when it did not come from fetching the page.
+func FailureCode(err error) string {
+	var (
+		fe     *fetchError
+		dnsErr *net.DNSError
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &fe):
+		return fe.Code
+	case errors.Is(err, netguard.ErrBlocked):
+		return entity.FailureBlocked
+	case errors.As(err, &dnsErr):
+		return entity.FailureDNS
+	}
+	return entity.FailureInternal
+}
+
+// fetchWithRetry runs fetchPage, retrying transient failures with
+// exponential backoff. Every failed attempt is recorded. A final HTTP error
+// status is returned as a response, not an error, so the page is still
+// analysed.
+func (uc *ScrapingUseCase) fetchWithRetry(ctx context.Context, targetURL string, userID int64, opts entity.ScrapeOptions, creds *entity.RequestCredentials, transport http.RoundTripper, jar http.CookieJar) (*http.Response, []entity.RedirectHop, error) {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, hops, err := uc.fetchPage(ctx, targetURL, opts, creds, transport, jar)
+
+		var code, message string
+		var statusCode int
+		if err != nil {
+			code, message = classifyFetchError(err), err.Error()
+		} else if resp.StatusCode >= 400 {
+			statusCode = resp.StatusCode
+			code, message = classifyStatus(resp.StatusCode), resp.Status
+		}
+		if code == "" {
+			return resp, hops, nil
+		}
+
+		wait := uc.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+		maxWait := time.Duration(uc.config.Scraping.Retry.MaxBackoffMs) * time.Millisecond
+		retry := isRetryable(code) && attempt < maxAttempts && ctx.Err() == nil
+		if retry && wait > maxWait {
+			message = fmt.Sprintf("%s (Retry-After %s exceeds the %s limit)", message, wait, maxWait)
+			retry = false
+		}
+
+		uc.recordFailure(&entity.ScrapeFailure{
+			UserID:     userID,
+			URL:        targetURL,
+			Attempt:    attempt,
+			Code:       code,
+			Message:    message,
+			StatusCode: statusCode,
+			Retried:    retry,
+			CreatedAt:  time.Now(),
+		})
+
+		if !retry {
+			if err != nil {
+				return nil, nil, &fetchError{Code: code, Err: err}
+			}
+			return resp, hops, nil
+		}
+
+		log.Printf("⚠️  Attempt %d/%d for %s failed (%s), retrying in %s", attempt, maxAttempts, targetURL, code, wait.Round(time.Millisecond))
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, &fetchError{Code: code, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is the exponential delay before retrying attempt, with jitter
+// between half and the full delay.
+func (uc *ScrapingUseCase) backoff(attempt int) time.Duration {
+	cfg := uc.config.Scraping.Retry
+	delay := time.Duration(cfg.InitialBackoffMs) * time.Millisecond
+	maxDelay := time.Duration(cfg.MaxBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+func (uc *ScrapingUseCase) recordFailure(failure *entity.ScrapeFailure) {
+	if uc.failures == nil {
+		return
+	}
+	if err := uc.failures.Save(failure); err != nil {
+		log.Printf("❌ Error saving scrape failure for %s: %v", failure.URL, err)
+	}
+}
+
+// parseRetryAfter accepts both forms of the header: delay in seconds or an
+// HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isRetryable(code string) bool {
+	switch code {
+	case entity.FailureTimeout, entity.FailureConnectionReset, entity.FailureRateLimited, entity.FailureHTTP5xx:
+		return true
+	}
+	return false
+}
+
+func classifyStatus(statusCode int) string {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return entity.FailureRateLimited
+	case statusCode >= 500:
+		return entity.FailureHTTP5xx
+	case statusCode >= 400:
+		return entity.FailureHTTP4xx
+	}
+	return ""
+}
+
+func classifyFetchError(err error) string {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		certErr      *tls.CertificateVerificationError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	switch {
+	case errors.Is(err, netguard.ErrBlocked):
+		return entity.FailureBlocked
+	case errors.Is(err, errTooManyRedirects):
+		return entity.FailureTooManyRedirects
+	case errors.As(err, &dnsErr):
+		return entity.FailureDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return entity.FailureTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return entity.FailureTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return entity.FailureConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return entity.FailureConnectionReset
+	}
+	return entity.FailureNetwork
+}
+
+// GetFailures lists the failed attempts of userID's scrapes, optionally
+// filtered by failure code and URL.
+func (uc *ScrapingUseCase) GetFailures(userID int64, code, url string, page, perPage int) (*entity.PaginatedScrapeFailures, error) {
+	if uc.failures == nil {
+		return nil, pkgerrors.InternalError("failure log is not available", nil)
+	}
+	paginationReq := entity.NewPaginationRequest(page, perPage)
+
+	failures, totalCount, err := uc.failures.FindByUserIDPaginated(userID, code, url, paginationReq)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get scrape failures", err)
+	}
+
+	return &entity.PaginatedScrapeFailures{
+		Data:       failures,
+		Pagination: entity.NewPaginationResponse(paginationReq.Page, paginationReq.PerPage, totalCount),
+	}, nil
+}
@@ -52,6 +52,15 @@ func (uc *ScheduleUseCase) CreateSchedule(req *entity.CreateScheduleRequest, use
 	if err := uc.validator.ValidateCronExpression(req.CronExpr); err != nil {
 		return nil, pkgerrors.ValidationError(err.Error())
 	}
+	if err := uc.scrapingUC.ValidateOptions(req.Options); err != nil {
+		return nil, err
+	}
+	if err := uc.scrapingUC.ValidateSessionProfile(req.Options, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.scrapingUC.ValidateCredentials(req.Credentials); err != nil {
+		return nil, err
+	}
 
 	nextRun, err := uc.calculateNextRun(req.CronExpr)
 	if err != nil {
@@ -66,6 +75,10 @@ func (uc *ScheduleUseCase) CreateSchedule(req *entity.CreateScheduleRequest, use
 		Active:   true,
 		NextRun:  &nextRun,
 		RunCount: 0,
+		Options:  req.Options,
+	}
+	if !req.Credentials.IsEmpty() {
+		schedule.Credentials = req.Credentials
 	}
 
 	if err := uc.scheduleRepo.Create(schedule); err != nil {
@@ -81,7 +94,7 @@ func (uc *ScheduleUseCase) CreateSchedule(req *entity.CreateScheduleRequest, use
 	uc.mu.Unlock()
 
 	log.Printf("✅ Schedule created: %s (ID: %d) - Next run: %v", schedule.Name, schedule.ID, nextRun)
-	return schedule, nil
+	return redactSchedule(schedule), nil
 }
 
 func (uc *ScheduleUseCase) GetSchedulesByUser(userID int64) ([]*entity.Schedule, error) {
@@ -89,6 +102,9 @@ func (uc *ScheduleUseCase) GetSchedulesByUser(userID int64) ([]*entity.Schedule,
 	if err != nil {
 		return nil, pkgerrors.DatabaseError("get user schedules", err)
 	}
+	for i, schedule := range schedules {
+		schedules[i] = redactSchedule(schedule)
+	}
 	return schedules, nil
 }
 
@@ -97,7 +113,7 @@ func (uc *ScheduleUseCase) GetSchedule(id int64) (*entity.Schedule, error) {
 	if err != nil {
 		return nil, pkgerrors.DatabaseError("get schedule", err)
 	}
-	return schedule, nil
+	return redactSchedule(schedule), nil
 }
 
 func (uc *ScheduleUseCase) UpdateSchedule(id int64, req *entity.UpdateScheduleRequest, userID int64) (*entity.Schedule, error) {
@@ -148,6 +164,27 @@ func (uc *ScheduleUseCase) UpdateSchedule(id int64, req *entity.UpdateScheduleRe
 		schedule.Active = *req.Active
 	}
 
+	if req.Options != nil {
+		if err := uc.scrapingUC.ValidateOptions(req.Options); err != nil {
+			return nil, err
+		}
+		if err := uc.scrapingUC.ValidateSessionProfile(req.Options, userID); err != nil {
+			return nil, err
+		}
+		schedule.Options = req.Options
+	}
+
+	if req.Credentials != nil {
+		creds := req.Credentials.MergeRedacted(schedule.Credentials)
+		if err := uc.scrapingUC.ValidateCredentials(creds); err != nil {
+			return nil, err
+		}
+		if creds.IsEmpty() {
+			creds = nil
+		}
+		schedule.Credentials = creds
+	}
+
 	if err := uc.scheduleRepo.Update(schedule); err != nil {
 		return nil, pkgerrors.DatabaseError("update schedule", err)
 	}
@@ -158,7 +195,17 @@ func (uc *ScheduleUseCase) UpdateSchedule(id int64, req *entity.UpdateScheduleRe
 	}
 
 	log.Printf("✅ Schedule updated: %s (ID: %d)", schedule.Name, schedule.ID)
-	return schedule, nil
+	return redactSchedule(schedule), nil
+}
+
+// redactSchedule returns a copy of schedule safe to send to clients.
+func redactSchedule(schedule *entity.Schedule) *entity.Schedule {
+	if schedule == nil || schedule.Credentials == nil {
+		return schedule
+	}
+	redacted := *schedule
+	redacted.Credentials = schedule.Credentials.Redacted()
+	return &redacted
 }
 
 func (uc *ScheduleUseCase) DeleteSchedule(id int64, userID int64) error {
@@ -317,7 +364,11 @@ func (uc *ScheduleUseCase) executeScheduleByID(scheduleID int64, scheduleName, s
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	_, err = uc.scrapingUC.ScrapeURL(ctx, schedule.URL, schedule.UserID)
+	_, err = uc.scrapingUC.Scrape(ctx, &entity.ScrapeRequest{
+		URL:         schedule.URL,
+		Options:     schedule.Options,
+		Credentials: schedule.Credentials,
+	}, schedule.UserID)
 	if err != nil {
 		log.Printf("❌ Error executing scheduled scraping %d: %v", scheduleID, err)
 	} else {
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/config"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/validator"
+)
+
+var (
+	errJobCancelled = errors.New("job cancelled")
+	errShuttingDown = errors.New("server shutting down")
+)
+
+// JobUseCase queues scrapes in the database and runs them on a bounded pool
+// of workers.
+type JobUseCase struct {
+	jobRepo    repository.ScrapeJobRepository
+	scrapingUC *ScrapingUseCase
+	config     *config.Config
+	validator  *validator.Validator
+	wake       chan struct{}
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	running    map[int64]context.CancelCauseFunc
+	// cancelled holds the jobs cancelled after being claimed but before
+	// run registered them
+	cancelled map[int64]bool
+	isStarted bool
+}
+
+func NewJobUseCase(jobRepo repository.ScrapeJobRepository, scrapingUC *ScrapingUseCase, cfg *config.Config) *JobUseCase {
+	return &JobUseCase{
+		jobRepo:    jobRepo,
+		scrapingUC: scrapingUC,
+		config:     cfg,
+		validator:  validator.NewValidator(),
+		wake:       make(chan struct{}, 1),
+		running:    make(map[int64]context.CancelCauseFunc),
+		cancelled:  make(map[int64]bool),
+	}
+}
+
+// Submit validates scrapeReq and queues it. The job is returned right away,
+// pending.
+func (uc *JobUseCase) Submit(scrapeReq *entity.ScrapeRequest, userID int64) (*entity.ScrapeJob, error) {
+	if err := uc.validator.ValidateURL(scrapeReq.URL); err != nil {
+		return nil, pkgerrors.ValidationError(err.Error())
+	}
+	if err := uc.scrapingUC.ValidateOptions(scrapeReq.Options); err != nil {
+		return nil, err
+	}
+	if err := uc.scrapingUC.ValidateSessionProfile(scrapeReq.Options, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.scrapingUC.ValidateCredentials(scrapeReq.Credentials); err != nil {
+		return nil, err
+	}
+
+	job := &entity.ScrapeJob{
+		UserID:  userID,
+		URL:     scrapeReq.URL,
+		Options: scrapeReq.Options,
+		Status:  entity.JobPending,
+	}
+	if !scrapeReq.Credentials.IsEmpty() {
+		job.Credentials = scrapeReq.Credentials
+	}
+	if err := uc.jobRepo.Create(job); err != nil {
+		return nil, pkgerrors.DatabaseError("create scrape job", err)
+	}
+
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+func (uc *JobUseCase) GetJob(id, userID int64) (*entity.ScrapeJob, error) {
+	job, err := uc.jobRepo.FindByID(id)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get scrape job", err)
+	}
+	if job == nil {
+		return nil, pkgerrors.NotFoundError("job")
+	}
+	if job.UserID != userID {
+		return nil, pkgerrors.New(
+			pkgerrors.CodeAuthorization,
+			"unauthorized: user does not own this job",
+			pkgerrors.ErrUnauthorized,
+		)
+	}
+	return job, nil
+}
+
+// CancelJob cancels a pending job, or stops a running one. Finished jobs are
+// left untouched.
+func (uc *JobUseCase) CancelJob(id, userID int64) (*entity.ScrapeJob, error) {
+	job, err := uc.GetJob(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, pkgerrors.ValidationError("job already " + job.Status)
+	}
+
+	cancelled, err := uc.jobRepo.CancelPending(id)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("cancel scrape job", err)
+	}
+	if !cancelled {
+		uc.mu.Lock()
+		if cancel, ok := uc.running[id]; ok {
+			cancel(errJobCancelled)
+		} else {
+			// claimed but not started yet: run stops it as soon as it starts
+			uc.cancelled[id] = true
+		}
+		uc.mu.Unlock()
+	}
+
+	job, err = uc.GetJob(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() && job.Status != entity.JobCancelled {
+		// it finished before the cancellation reached it
+		uc.mu.Lock()
+		delete(uc.cancelled, id)
+		uc.mu.Unlock()
+		return nil, pkgerrors.ValidationError("job already " + job.Status)
+	}
+	log.Printf("🛑 Cancelled scrape job %d", id)
+	return job, nil
+}
+
+// Start requeues jobs interrupted by the last shutdown and starts the
+// workers.
+func (uc *JobUseCase) Start() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.isStarted {
+		log.Println("⚠️  Job workers are already started")
+		return
+	}
+
+	if n, err := uc.jobRepo.Requeue(); err != nil {
+		log.Printf("❌ Error requeueing interrupted jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("🔄 Requeued %d interrupted scrape jobs", n)
+	}
+
+	uc.stop = make(chan struct{})
+	workers := uc.config.Scraping.Jobs.Workers
+	for i := 0; i < workers; i++ {
+		uc.wg.Add(1)
+		go uc.worker()
+	}
+	uc.isStarted = true
+	log.Printf("✅ Started %d scrape job workers", workers)
+}
+
+// Stop interrupts running jobs and waits for the workers to exit. The
+// interrupted jobs go back to the queue.
+func (uc *JobUseCase) Stop() {
+	uc.mu.Lock()
+	if !uc.isStarted {
+		uc.mu.Unlock()
+		return
+	}
+	close(uc.stop)
+	for _, cancel := range uc.running {
+		cancel(errShuttingDown)
+	}
+	uc.isStarted = false
+	uc.mu.Unlock()
+
+	uc.wg.Wait()
+	if _, err := uc.jobRepo.Requeue(); err != nil {
+		log.Printf("❌ Error requeueing interrupted jobs: %v", err)
+	}
+}
+
+func (uc *JobUseCase) worker() {
+	defer uc.wg.Done()
+
+	poll := time.NewTicker(time.Duration(uc.config.Scraping.Jobs.PollInterval) * time.Second)
+	defer poll.Stop()
+
+	for {
+		// drain the queue before going idle
+		for {
+			select {
+			case <-uc.stop:
+				return
+			default:
+			}
+			job, err := uc.jobRepo.ClaimNext()
+			if err != nil {
+				log.Printf("❌ Error claiming scrape job: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			uc.run(job)
+		}
+
+		select {
+		case <-uc.stop:
+			return
+		case <-uc.wake:
+		case <-poll.C:
+		}
+	}
+}
+
+func (uc *JobUseCase) run(job *entity.ScrapeJob) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	uc.mu.Lock()
+	if !uc.isStarted {
+		uc.mu.Unlock()
+		return
+	}
+	uc.running[job.ID] = cancel
+	if uc.cancelled[job.ID] {
+		cancel(errJobCancelled)
+	}
+	uc.mu.Unlock()
+	defer func() {
+		uc.mu.Lock()
+		delete(uc.running, job.ID)
+		delete(uc.cancelled, job.ID)
+		uc.mu.Unlock()
+	}()
+
+	result, err := uc.scrapingUC.scrape(ctx, job.Request(), job.UserID)
+
+	if err != nil && errors.Is(context.Cause(ctx), errShuttingDown) {
+		// left running; Stop puts it back in the queue
+		return
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case err == nil:
+		job.Status = entity.JobCompleted
+		job.ResultID = &result.ID
+	case errors.Is(context.Cause(ctx), errJobCancelled):
+		job.Status = entity.JobCancelled
+	default:
+		job.Status = entity.JobFailed
+		job.Error = err.Error()
+	}
+
+	if err := uc.jobRepo.Finish(job); err != nil {
+		log.Printf("❌ Error saving scrape job %d: %v", job.ID, err)
+	}
+	log.Printf("✅ Scrape job %d for %s finished: %s", job.ID, job.URL, job.Status)
+	// only now does the job show its status and result
+	uc.scrapingUC.notify(job.UserID)
+}
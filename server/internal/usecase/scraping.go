@@ -4,124 +4,409 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
+	"sync"
 	"time"
 	"webscraper-v2/internal/domain/entity"
 	"webscraper-v2/internal/domain/repository"
 	"webscraper-v2/internal/infrastructure/config"
+	"webscraper-v2/internal/infrastructure/proxy"
 	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/fingerprint"
+	"webscraper-v2/pkg/netguard"
 	"webscraper-v2/pkg/validator"
 
 	"golang.org/x/net/html"
 )
 
-var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
-
 // ResultNotifier is notified after each scraping result is persisted.
 // Implemented by the SSE hub in the presentation layer.
 type ResultNotifier interface {
 	Notify(userID int64)
 }
 
+// SessionProvider logs in with session profiles and hands out the resulting
+// cookie jars. Implemented by SessionUseCase.
+type SessionProvider interface {
+	SessionJar(ctx context.Context, profileID, userID int64, transport http.RoundTripper) (http.CookieJar, error)
+	IsLoggedOut(profileID int64, resp *http.Response) bool
+	InvalidateSession(profileID int64)
+	AuthorizeProfile(profileID, userID int64) error
+}
+
 type ScrapingUseCase struct {
-	repo      repository.ScrapingRepository
-	config    *config.Config
-	validator *validator.Validator
-	notifier  ResultNotifier
+	repo       repository.ScrapingRepository
+	failures   repository.ScrapeFailureRepository
+	config     *config.Config
+	validator  *validator.Validator
+	notifier   ResultNotifier
+	sessions   SessionProvider
+	extractors *ExtractorRegistry
+	proxies    *proxy.Manager
+	guard      *netguard.Guard
+	transports sync.Map
+	// fingerprints is nil when no rule file was loaded
+	fingerprints *fingerprint.Engine
 }
 
-func NewScrapingUseCase(repo repository.ScrapingRepository, cfg *config.Config) *ScrapingUseCase {
-	return &ScrapingUseCase{
-		repo:      repo,
-		config:    cfg,
-		validator: validator.NewValidator(),
+func NewScrapingUseCase(repo repository.ScrapingRepository, failures repository.ScrapeFailureRepository, cfg *config.Config, proxies *proxy.Manager, guard *netguard.Guard) *ScrapingUseCase {
+	uc := &ScrapingUseCase{
+		repo:       repo,
+		failures:   failures,
+		config:     cfg,
+		validator:  validator.NewValidator(),
+		extractors: NewExtractorRegistry(),
+		proxies:    proxies,
+		guard:      guard,
 	}
+	uc.registerBuiltinExtractors()
+	return uc
 }
 
 func (uc *ScrapingUseCase) SetNotifier(n ResultNotifier) {
 	uc.notifier = n
 }
 
+func (uc *ScrapingUseCase) SetSessionProvider(p SessionProvider) {
+	uc.sessions = p
+}
+
+func (uc *ScrapingUseCase) SetFingerprints(e *fingerprint.Engine) {
+	uc.fingerprints = e
+}
+
+// ValidateSessionProfile checks that the session profile referenced by ov,
+// if any, exists and belongs to userID.
+func (uc *ScrapingUseCase) ValidateSessionProfile(ov *entity.ScrapeOptionsOverride, userID int64) error {
+	if ov == nil || ov.SessionProfileID == nil || *ov.SessionProfileID == 0 {
+		return nil
+	}
+	if uc.sessions == nil {
+		return pkgerrors.ValidationError("session profiles are not available")
+	}
+	return uc.sessions.AuthorizeProfile(*ov.SessionProfileID, userID)
+}
+
+// RegisterExtractor adds an in-house extractor to the registry. It must be
+// called before the server starts handling requests.
+func (uc *ScrapingUseCase) RegisterExtractor(reg ExtractorRegistration) error {
+	return uc.extractors.Register(reg)
+}
+
+func (uc *ScrapingUseCase) Extractors() []ExtractorRegistration {
+	return uc.extractors.List()
+}
+
 func (uc *ScrapingUseCase) ScrapeURL(ctx context.Context, targetURL string, userID int64) (*entity.ScrapingResult, error) {
+	return uc.Scrape(ctx, &entity.ScrapeRequest{URL: targetURL}, userID)
+}
+
+// Scrape fetches and analyses scrapeReq.URL. Its options are applied on top
+// of the configured defaults and its credentials are sent with the request.
+// The user is notified once the result is saved.
+func (uc *ScrapingUseCase) Scrape(ctx context.Context, scrapeReq *entity.ScrapeRequest, userID int64) (*entity.ScrapingResult, error) {
+	result, err := uc.scrape(ctx, scrapeReq, userID)
+	if err != nil {
+		return nil, err
+	}
+	uc.notify(userID)
+	return result, nil
+}
+
+// scrape is Scrape without the notification, for callers that have more to
+// record before the user should look.
+func (uc *ScrapingUseCase) scrape(ctx context.Context, scrapeReq *entity.ScrapeRequest, userID int64) (*entity.ScrapingResult, error) {
+	targetURL := scrapeReq.URL
 	if err := uc.validator.ValidateURL(targetURL); err != nil {
 		return nil, pkgerrors.ValidationError(err.Error())
 	}
-
-	startTime := time.Now()
-	var redirectChain []string
-
-	client := &http.Client{
-		Timeout: time.Duration(uc.config.Scraping.Timeout) * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if len(via) >= 10 {
-				return fmt.Errorf("stopped after 10 redirects")
-			}
-			redirectChain = append(redirectChain, req.URL.String())
-			return nil
-		},
+	if err := uc.ValidateOptions(scrapeReq.Options); err != nil {
+		return nil, err
 	}
+	if err := uc.ValidateCredentials(scrapeReq.Credentials); err != nil {
+		return nil, err
+	}
+	if err := uc.checkDestination(ctx, targetURL); err != nil {
+		return nil, err
+	}
+	opts := uc.DefaultOptions().Apply(scrapeReq.Options)
+	creds := scrapeReq.Credentials
 
-	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
+	sel, err := uc.selectProxy(opts)
 	if err != nil {
-		return nil, pkgerrors.InternalError("failed to create request", err)
+		return nil, pkgerrors.InternalError("failed to select proxy", err)
 	}
+	transport := uc.transportFor(sel)
 
-	req.Header.Set("User-Agent", uc.config.Scraping.UserAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	var jar http.CookieJar
+	if opts.SessionProfileID != 0 {
+		if uc.sessions == nil {
+			return nil, pkgerrors.ValidationError("session profiles are not available")
+		}
+		if jar, err = uc.sessions.SessionJar(ctx, opts.SessionProfileID, userID, transport); err != nil {
+			return nil, err
+		}
+	}
 
-	resp, err := client.Do(req)
+	startTime := time.Now()
+	resp, hops, err := uc.fetchWithRetry(ctx, targetURL, userID, opts, creds, transport, jar)
 	if err != nil {
-		return nil, pkgerrors.InternalError("failed to fetch URL", err)
+		sel.reportFailure(err)
+		return nil, fetchFailure(err)
 	}
-	defer resp.Body.Close()
+	if jar != nil && uc.sessions.IsLoggedOut(opts.SessionProfileID, resp) {
+		// the cached session is no longer valid: log in again and retry once
+		resp.Body.Close()
+		log.Printf("🔐 Session profile %d was logged out, logging in again", opts.SessionProfileID)
+		uc.sessions.InvalidateSession(opts.SessionProfileID)
+		if jar, err = uc.sessions.SessionJar(ctx, opts.SessionProfileID, userID, transport); err != nil {
+			return nil, err
+		}
+		startTime = time.Now()
+		resp, hops, err = uc.fetchWithRetry(ctx, targetURL, userID, opts, creds, transport, jar)
+		if err != nil {
+			sel.reportFailure(err)
+			return nil, fetchFailure(err)
+		}
+	}
+	// resp is replaced when a client-side redirect is followed
+	defer func() { resp.Body.Close() }()
+	sel.reportSuccess()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, pkgerrors.InternalError("failed to read response body", err)
+	visited := map[string]bool{targetURL: true}
+	for _, hop := range hops {
+		visited[hop.Location] = true
 	}
+	var followed []entity.ClientRedirect
+	pageCreds := creds
+	var result *entity.ScrapingResult
+	var isHTML bool
+	for {
+		result = &entity.ScrapingResult{
+			UserID:          userID,
+			URL:             targetURL,
+			StatusCode:      resp.StatusCode,
+			ContentType:     resp.Header.Get("Content-Type"),
+			XRobotsTag:      resp.Header.Get("X-Robots-Tag"),
+			RedirectChain:   []string{},
+			RedirectHops:    hops,
+			ClientRedirects: []entity.ClientRedirect{},
+			SEOIssues:       []entity.SEOIssue{},
+			MixedContent:    []entity.MixedContent{},
+			Resources:       []entity.Resource{},
+			Technologies:    []entity.Technology{},
+			FinalURL:        resp.Request.URL.String(),
+			Proxy:           sel.redacted(),
+			CreatedAt:       time.Now(),
+		}
+		page := &PageResponse{
+			URL:         targetURL,
+			FinalURL:    result.FinalURL,
+			StatusCode:  resp.StatusCode,
+			Header:      resp.Header,
+			Options:     opts,
+			Client:      uc.probeClient(transport, jar, pageCreds),
+			credentials: pageCreds,
+		}
+		if isHTML, err = uc.analyseResponse(ctx, resp, page, result, startTime); err != nil {
+			return nil, err
+		}
 
-	loadTime := time.Since(startTime).Milliseconds()
-	doc, err := html.Parse(strings.NewReader(string(body)))
-	if err != nil {
-		return nil, pkgerrors.InternalError("failed to parse HTML", err)
-	}
-
-	result := &entity.ScrapingResult{
-		UserID:        userID,
-		URL:           targetURL,
-		StatusCode:    resp.StatusCode,
-		ContentType:   resp.Header.Get("Content-Type"),
-		XRobotsTag:    resp.Header.Get("X-Robots-Tag"),
-		LoadTime:      loadTime,
-		RedirectChain: redirectChain,
-		FinalURL:      resp.Request.URL.String(),
-		CreatedAt:     time.Now(),
-	}
-
-	uc.extractMetadata(doc, result)
-	uc.extractCanonical(doc, result)
-	uc.extractSchemaOrg(doc, result)
-	uc.extractLinks(doc, result, targetURL)
-	uc.extractImages(doc, result, targetURL)
-	uc.extractHeaders(doc, result)
-	uc.validateHeadings(result)
-	uc.extractFavicon(ctx, targetURL, result)
-	uc.calculateWordCount(string(body), result)
-	uc.calculateSEOScore(result)
+		next := nextClientRedirect(result, visited)
+		if !opts.FollowClientRedirects || next == nil || len(hops) >= opts.MaxRedirects {
+			break
+		}
+		if err := uc.checkDestination(ctx, next.Target); err != nil {
+			log.Printf("⚠️  Not following client-side redirect of %s: %v", result.FinalURL, err)
+			break
+		}
+		// the credentials stay with the host they were given for
+		nextCreds := creds
+		if !sameHost(targetURL, next.Target) {
+			nextCreds = nil
+		}
+		hopStart := time.Now()
+		nextResp, nextHops, err := uc.fetchWithRetry(ctx, next.Target, userID, opts, nextCreds, transport, jar)
+		if err != nil {
+			log.Printf("⚠️  Failed to follow client-side redirect to %s: %v", next.Target, err)
+			break
+		}
+		resp.Body.Close()
+		hops = append(hops, entity.RedirectHop{
+			URL:         result.FinalURL,
+			StatusCode:  result.StatusCode,
+			Type:        next.Type,
+			Location:    next.Target,
+			LatencyMs:   time.Since(hopStart).Milliseconds(),
+			CrossHost:   !sameHost(result.FinalURL, next.Target),
+			CrossScheme: schemeOf(result.FinalURL) != schemeOf(next.Target),
+			SetCookie:   resp.Header.Values("Set-Cookie"),
+		})
+		resp = nextResp
+		pageCreds = nextCreds
+
+		hops = append(hops, nextHops...)
+		next.Followed = true
+		followed = append(followed, *next)
+		visited[next.Target] = true
+		for _, hop := range nextHops {
+			visited[hop.Location] = true
+		}
+	}
+
+	for _, hop := range hops {
+		result.RedirectChain = append(result.RedirectChain, hop.Location)
+	}
+	result.RedirectIssues = auditRedirects(hops, uc.config.Scraping.LongRedirectChain)
+	if len(followed) > 0 {
+		issues := make([]entity.SEOIssue, 0, len(followed)+len(result.SEOIssues))
+		for _, redirect := range followed {
+			issues = append(issues, clientRedirectIssue(redirect))
+		}
+		result.ClientRedirects = append(followed, result.ClientRedirects...)
+		result.SEOIssues = append(issues, result.SEOIssues...)
+	}
+	if isHTML {
+		uc.calculateSEOScore(result)
+	}
 
 	if err := uc.repo.Save(result); err != nil {
 		return nil, pkgerrors.DatabaseError("save scraping result", err)
 	}
+	return result, nil
+}
+
+// analyseResponse reads the body of resp into result and, for HTML pages,
+// runs the extractors on it. It reports whether the page was HTML.
+func (uc *ScrapingUseCase) analyseResponse(ctx context.Context, resp *http.Response, page *PageResponse, result *entity.ScrapingResult, startTime time.Time) (bool, error) {
+	body := newLimitedBody(resp.Body, int64(page.Options.MaxBodyBytes))
+	reader, isHTML := sniffHTML(body, result.ContentType)
+	if !isHTML {
+		// images, PDFs, feeds...: keep the response metadata only
+		result.LoadTime = time.Since(startTime).Milliseconds()
+		result.BodyBytes = resp.ContentLength
+		if result.BodyBytes < 0 {
+			result.BodyBytes = 0
+		}
+		log.Printf("⚠️  Skipping HTML analysis of %s (content type %q)", result.FinalURL, result.ContentType)
+		return false, nil
+	}
+
+	doc, err := html.Parse(reader)
+	if err != nil {
+		return false, pkgerrors.InternalError("failed to parse HTML", err)
+	}
+	result.LoadTime = time.Since(startTime).Milliseconds()
+	result.BodyBytes = body.n
+	result.Truncated = body.truncated
+	if result.Truncated {
+		log.Printf("⚠️  Body of %s truncated at %d bytes", result.FinalURL, page.Options.MaxBodyBytes)
+	}
+
+	if err := uc.runExtractors(ctx, doc, page, result); err != nil {
+		return false, err
+	}
+	uc.calculateWordCount(doc, result)
+	uc.calculateSimHash(doc, result)
+	return true, nil
+}
 
+func (uc *ScrapingUseCase) notify(userID int64) {
 	if uc.notifier != nil && userID != 0 {
 		uc.notifier.Notify(userID)
 	}
+}
 
-	return result, nil
+// fetchPage performs the GET of a scrape, following redirects one hop at a
+// time so each hop can be recorded. A redirect back to a URL already visited
+// stops the chain and its response is returned as the page.
+func (uc *ScrapingUseCase) fetchPage(ctx context.Context, targetURL string, opts entity.ScrapeOptions, creds *entity.RequestCredentials, transport http.RoundTripper, jar http.CookieJar) (*http.Response, []entity.RedirectHop, error) {
+	client := &http.Client{
+		Transport: transport,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	// the timeout covers the whole chain
+	deadline := time.Now().Add(time.Duration(opts.Timeout) * time.Second)
+
+	origin, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := origin
+	visited := map[string]bool{current.String(): true}
+	var hops []entity.RedirectHop
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", current.String(), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("User-Agent", opts.UserAgent)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+		// credentials are only sent to the host they were given for
+		if current.Host == origin.Host {
+			applyCredentials(req, creds)
+		}
+
+		client.Timeout = time.Until(deadline)
+		if client.Timeout <= 0 {
+			return nil, nil, context.DeadlineExceeded
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		location := resp.Header.Get("Location")
+		if !opts.FollowRedirects || !isRedirect(resp.StatusCode) || location == "" {
+			return resp, hops, nil
+		}
+		next, err := current.Parse(location)
+		if err != nil {
+			// an unusable Location is reported as the page itself
+			return resp, hops, nil
+		}
+
+		hops = append(hops, entity.RedirectHop{
+			URL:         current.String(),
+			StatusCode:  resp.StatusCode,
+			Type:        entity.RedirectHTTP,
+			Location:    next.String(),
+			LatencyMs:   time.Since(start).Milliseconds(),
+			CrossHost:   !strings.EqualFold(next.Host, current.Host),
+			CrossScheme: next.Scheme != current.Scheme,
+			SetCookie:   resp.Header.Values("Set-Cookie"),
+		})
+		if visited[next.String()] {
+			return resp, hops, nil
+		}
+		if len(hops) > opts.MaxRedirects {
+			resp.Body.Close()
+			return nil, nil, fmt.Errorf("%w (stopped after %d redirects)", errTooManyRedirects, opts.MaxRedirects)
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		visited[next.String()] = true
+		current = next
+	}
+}
+
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
 }
 
 func (uc *ScrapingUseCase) GetAllResults(userID int64) ([]*entity.ScrapingResult, error) {
@@ -177,10 +462,17 @@ func (uc *ScrapingUseCase) DeleteResult(id int64, userID int64) error {
 	return nil
 }
 
-func (uc *ScrapingUseCase) GetAllResultsPaginated(userID int64, page, perPage int) (*entity.PaginatedScrapingResults, error) {
+func (uc *ScrapingUseCase) GetAllResultsPaginated(userID int64, page, perPage int, sort string) (*entity.PaginatedScrapingResults, error) {
+	switch sort {
+	case "":
+		sort = entity.ResultSortCreated
+	case entity.ResultSortCreated, entity.ResultSortPublished:
+	default:
+		return nil, pkgerrors.ValidationError("sort must be created_at or published_at")
+	}
 	paginationReq := entity.NewPaginationRequest(page, perPage)
 
-	results, totalCount, err := uc.repo.FindAllByUserIDPaginated(userID, paginationReq)
+	results, totalCount, err := uc.repo.FindAllByUserIDPaginated(userID, sort, paginationReq)
 	if err != nil {
 		return nil, pkgerrors.DatabaseError("get paginated results", err)
 	}
@@ -193,6 +485,214 @@ func (uc *ScrapingUseCase) GetAllResultsPaginated(userID int64, page, perPage in
 	}, nil
 }
 
+// DefaultOptions returns the scrape options configured in config.yaml.
+func (uc *ScrapingUseCase) DefaultOptions() entity.ScrapeOptions {
+	cfg := uc.config.Scraping
+	return entity.ScrapeOptions{
+		ExtractLinks:    true,
+		ExtractImages:   cfg.ExtractImages,
+		ExtractHeaders:  cfg.ExtractHeaders,
+		ExtractFavicon:  cfg.ExtractFavicon,
+		FollowRedirects: true,
+		MaxRedirects:    cfg.MaxRedirects,
+		MaxLinks:        cfg.MaxLinks,
+		MaxImages:       cfg.MaxImages,
+		Timeout:         cfg.Timeout,
+		UserAgent:       cfg.UserAgent,
+		MaxBodyBytes:    cfg.MaxBodyBytes,
+		MaxAttempts:     cfg.Retry.MaxAttempts,
+	}
+}
+
+// ValidateOptions checks the limits of a per-request or per-schedule override.
+func (uc *ScrapingUseCase) ValidateOptions(ov *entity.ScrapeOptionsOverride) error {
+	if ov == nil {
+		return nil
+	}
+	if ov.MaxRedirects != nil {
+		if err := uc.validator.ValidateRange(*ov.MaxRedirects, "max_redirects", 0, 30); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.MaxLinks != nil {
+		if err := uc.validator.ValidateRange(*ov.MaxLinks, "max_links", 1, 5000); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.MaxImages != nil {
+		if err := uc.validator.ValidateRange(*ov.MaxImages, "max_images", 1, 5000); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.Timeout != nil {
+		if err := uc.validator.ValidateRange(*ov.Timeout, "timeout", 1, 300); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.MaxBodyBytes != nil {
+		if err := uc.validator.ValidateRange(*ov.MaxBodyBytes, "max_body_bytes", 1<<10, 100<<20); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.MaxAttempts != nil {
+		if err := uc.validator.ValidateRange(*ov.MaxAttempts, "max_attempts", 1, 10); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.UserAgent != nil {
+		if err := uc.validator.ValidateMaxLength(*ov.UserAgent, "user_agent", 512); err != nil {
+			return pkgerrors.ValidationError(err.Error())
+		}
+	}
+	if ov.Proxy != nil && *ov.Proxy != "" && *ov.Proxy != ProxyDirect {
+		if uc.proxies == nil {
+			return pkgerrors.ValidationError(fmt.Sprintf("unknown proxy pool: %s", *ov.Proxy))
+		}
+		if _, ok := uc.proxies.Pool(*ov.Proxy); !ok {
+			return pkgerrors.ValidationError(fmt.Sprintf("unknown proxy pool: %s", *ov.Proxy))
+		}
+	}
+	for name := range ov.Extractors {
+		if !uc.extractors.Has(name) {
+			return pkgerrors.ValidationError(fmt.Sprintf("unknown extractor: %s", name))
+		}
+	}
+	return nil
+}
+
+func (uc *ScrapingUseCase) registerBuiltinExtractors() {
+	builtins := []ExtractorRegistration{
+		{Name: "metadata", Order: 10, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractMetadata(doc, b.Result())
+				return nil
+			})},
+		{Name: "canonical", Order: 20, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractCanonical(doc, b.Result())
+				return nil
+			})},
+		{Name: "schema_org", Order: 30, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractSchemaOrg(doc, b.Result())
+				return nil
+			})},
+		{Name: "links", Order: 40, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractLinks(doc, b.Result(), page.FinalURL, page.Header, page.Options.MaxLinks)
+				return nil
+			})},
+		{Name: "images", Order: 50, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractImages(doc, b.Result(), page.URL, page.Options.MaxImages)
+				return nil
+			})},
+		{Name: "headers", Order: 60, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractHeaders(doc, b.Result())
+				uc.validateHeadings(b.Result())
+				return nil
+			})},
+		{Name: "favicon", Order: 70, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractFavicon(ctx, doc, b.Result(), page)
+				return nil
+			})},
+		{Name: "tables", Order: 80, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractTables(doc, b.Result())
+				return nil
+			})},
+		{Name: "client_redirects", Order: 90, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractClientRedirects(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+		{Name: "mixed_content", Order: 100, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractMixedContent(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+		{Name: "resources", Order: 110, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractResources(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+		// page_weight downloads every resource, so it only runs on request
+		{Name: "page_weight", Order: 120, Enabled: false, DependsOn: []string{"resources"}, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.probePageWeight(ctx, b.Result(), page)
+				return nil
+			})},
+		{Name: "technologies", Order: 130, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.detectTechnologies(doc, b.Result(), page)
+				return nil
+			})},
+		{Name: "privacy", Order: 140, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.auditPrivacy(doc, b.Result(), page)
+				return nil
+			})},
+		{Name: "contacts", Order: 150, Enabled: true, DependsOn: []string{"schema_org", "links"}, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractContacts(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+		{Name: "article", Order: 160, Enabled: true, DependsOn: []string{"metadata", "schema_org"}, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractArticle(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+		{Name: "forms", Order: 170, Enabled: true, Extractor: ExtractorFunc(
+			func(ctx context.Context, doc *html.Node, page *PageResponse, b *ResultBuilder) error {
+				uc.extractForms(doc, b.Result(), page.FinalURL)
+				return nil
+			})},
+	}
+	for _, reg := range builtins {
+		if err := uc.extractors.Register(reg); err != nil {
+			log.Fatalf("❌ Failed to register extractor %s: %v", reg.Name, err)
+		}
+	}
+}
+
+// extractorEnabled resolves whether reg runs for a scrape: the Extractors map
+// wins, then the legacy Extract* flags, then the registration default.
+func extractorEnabled(reg ExtractorRegistration, opts entity.ScrapeOptions) bool {
+	if enabled, ok := opts.Extractors[reg.Name]; ok {
+		return enabled
+	}
+	switch reg.Name {
+	case "links":
+		return opts.ExtractLinks
+	case "images":
+		return opts.ExtractImages
+	case "headers":
+		return opts.ExtractHeaders
+	case "favicon":
+		return opts.ExtractFavicon
+	}
+	return reg.Enabled
+}
+
+func (uc *ScrapingUseCase) runExtractors(ctx context.Context, doc *html.Node, page *PageResponse, result *entity.ScrapingResult) error {
+	plan, err := uc.extractors.Plan(func(reg ExtractorRegistration) bool {
+		return extractorEnabled(reg, page.Options)
+	})
+	if err != nil {
+		return pkgerrors.InternalError("failed to plan extractors", err)
+	}
+
+	builder := newResultBuilder(result)
+	for _, reg := range plan {
+		if err := reg.Extractor.Extract(ctx, doc, page, builder); err != nil {
+			log.Printf("⚠️  Extractor %s failed for %s: %v", reg.Name, page.URL, err)
+		}
+	}
+	return nil
+}
+
 // — Extraction —
 
 func (uc *ScrapingUseCase) extractMetadata(n *html.Node, result *entity.ScrapingResult) {
@@ -329,49 +829,13 @@ func (uc *ScrapingUseCase) extractSchemaOrg(n *html.Node, result *entity.Scrapin
 	})
 }
 
-func (uc *ScrapingUseCase) extractLinks(n *html.Node, result *entity.ScrapingResult, baseURL string) {
-	linkMap := make(map[string]bool)
-	baseParsed, _ := url.Parse(baseURL)
-
-	uc.traverseNode(n, func(node *html.Node) {
-		if node.Type == html.ElementNode && node.Data == "a" {
-			var href, rel string
-			for _, attr := range node.Attr {
-				switch attr.Key {
-				case "href":
-					href = strings.TrimSpace(attr.Val)
-				case "rel":
-					rel = attr.Val
-				}
-			}
-			if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
-				return
-			}
-			absoluteURL := uc.resolveURL(baseURL, href)
-			if absoluteURL == "" || linkMap[absoluteURL] {
-				return
-			}
-			linkMap[absoluteURL] = true
-
-			isInternal := false
-			if parsed, err := url.Parse(absoluteURL); err == nil && baseParsed != nil {
-				isInternal = parsed.Host == baseParsed.Host
-			}
-
-			result.Links = append(result.Links, entity.Link{
-				URL:        absoluteURL,
-				AnchorText: uc.getTextContent(node),
-				Rel:        rel,
-				IsInternal: isInternal,
-			})
-		}
-	})
-}
-
-func (uc *ScrapingUseCase) extractImages(n *html.Node, result *entity.ScrapingResult, baseURL string) {
+func (uc *ScrapingUseCase) extractImages(n *html.Node, result *entity.ScrapingResult, baseURL string, maxImages int) {
 	imageMap := make(map[string]bool)
 
 	uc.traverseNode(n, func(node *html.Node) {
+		if len(result.Images) >= maxImages {
+			return
+		}
 		if node.Type == html.ElementNode && node.Data == "img" {
 			var src, alt, title string
 			for _, attr := range node.Attr {
@@ -490,50 +954,24 @@ func (uc *ScrapingUseCase) calculateSEOScore(result *entity.ScrapingResult) {
 		score += int(float64(withAlt) / float64(len(result.Images)) * 10)
 	}
 
-	// No redirect chain (+10)
-	if len(result.RedirectChain) == 0 {
+	// No redirect chain, server or client-side (+10)
+	if len(result.RedirectChain) == 0 && len(result.ClientRedirects) == 0 {
 		score += 10
 	}
 
 	result.SEOScore = score
 }
 
-func (uc *ScrapingUseCase) extractFavicon(ctx context.Context, targetURL string, result *entity.ScrapingResult) {
-	// Usar el host final (post-redirect) para la búsqueda del favicon
-	probeURL := targetURL
-	if result.FinalURL != "" {
-		probeURL = result.FinalURL
-	}
-	parsedURL, err := url.Parse(probeURL)
-	if err != nil {
-		return
-	}
-
-	faviconURLs := []string{
-		fmt.Sprintf("%s://%s/favicon.ico", parsedURL.Scheme, parsedURL.Host),
-		fmt.Sprintf("%s://%s/favicon.png", parsedURL.Scheme, parsedURL.Host),
-		fmt.Sprintf("%s://%s/apple-touch-icon.png", parsedURL.Scheme, parsedURL.Host),
-	}
-
-	for _, faviconURL := range faviconURLs {
-		if uc.checkURLExists(ctx, faviconURL) {
-			result.Favicon = faviconURL
-			break
-		}
-	}
-}
-
-func (uc *ScrapingUseCase) checkURLExists(ctx context.Context, targetURL string) bool {
+func (uc *ScrapingUseCase) checkURLExists(ctx context.Context, page *PageResponse, targetURL string) bool {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "HEAD", targetURL, nil)
+	req, err := page.NewRequest(ctx, "HEAD", targetURL)
 	if err != nil {
 		return false
 	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := page.Client.Do(req)
 	if err != nil {
 		return false
 	}
@@ -542,9 +980,27 @@ func (uc *ScrapingUseCase) checkURLExists(ctx context.Context, targetURL string)
 	return resp.StatusCode == http.StatusOK
 }
 
-func (uc *ScrapingUseCase) calculateWordCount(content string, result *entity.ScrapingResult) {
-	text := htmlTagRe.ReplaceAllString(content, " ")
-	result.WordCount = len(strings.Fields(text))
+// calculateWordCount counts the words of the visible text, skipping
+// scripts, styles and other non-rendered elements.
+func (uc *ScrapingUseCase) calculateWordCount(doc *html.Node, result *entity.ScrapingResult) {
+	count := 0
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template":
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			count += len(strings.Fields(n.Data))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	result.WordCount = count
 }
 
 // — Helpers —
@@ -571,6 +1027,16 @@ func (uc *ScrapingUseCase) resolveURL(base, href string) string {
 	return baseURL.ResolveReference(hrefURL).String()
 }
 
+// htmlAttr returns the value of the attribute key of n, or "".
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
 func (uc *ScrapingUseCase) traverseNode(n *html.Node, fn func(*html.Node)) {
 	fn(n)
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/pkg/datetime"
+
+	"golang.org/x/net/html"
+)
+
+const maxAuthors = 10
+
+var (
+	// urlDatePattern finds /2024/03/15/, /2024-03-15 or /2024/03/ in a path
+	urlDatePattern = regexp.MustCompile(`/((?:19|20)\d{2})[/-](0?[1-9]|1[0-2])(?:[/-](0?[1-9]|[12]\d|3[01]))?(?:[/-]|$)`)
+	bylinePrefix   = regexp.MustCompile(`(?i)^(?:written by|posted by|by|por|escrito por|publicado por)\s+`)
+	authorSplit    = regexp.MustCompile(`\s*(?:,|&|\band\b|\by\b)\s*`)
+)
+
+// articleTypes are the schema.org types whose dates describe the page.
+var articleTypes = map[string]bool{
+	"article": true, "newsarticle": true, "blogposting": true, "reportagenewsarticle": true,
+	"analysisnewsarticle": true, "opinionnewsarticle": true, "scholarlyarticle": true,
+	"techarticle": true, "liveblogposting": true, "report": true, "socialmediaposting": true,
+}
+
+var (
+	publishedMeta = []string{
+		"article:published_time", "og:article:published_time", "pubdate", "publishdate", "publish-date",
+		"publish_date", "dc.date.issued", "dcterms.issued", "dc.date", "dcterms.created", "date",
+		"sailthru.date", "parsely-pub-date", "citation_publication_date",
+	}
+	modifiedMeta = []string{
+		"article:modified_time", "og:updated_time", "dc.date.modified", "dcterms.modified", "last-modified",
+	}
+	authorMeta = []string{
+		"author", "article:author", "parsely-author", "sailthru.author", "dc.creator", "citation_author",
+	}
+)
+
+// extractArticle resolves the publication and modification dates and the
+// authors of the page. Each is taken from the first source that has it:
+// JSON-LD, meta tags, microdata, <time datetime>, bylines and, for the
+// publication date, the URL. The author found also fills Author when the
+// page has no <meta name=author>.
+func (uc *ScrapingUseCase) extractArticle(doc *html.Node, result *entity.ScrapingResult, pageURL string) {
+	article := &result.Article
+	article.Authors = []string{}
+
+	setDate := func(dst **time.Time, source *string, value, from string) {
+		if *dst != nil {
+			return
+		}
+		t, err := datetime.Parse(strings.TrimSpace(value))
+		if err != nil || t.Year() < 1990 {
+			return
+		}
+		*dst = &t
+		*source = from
+	}
+	setAuthors := func(names []string, from string) {
+		if len(article.Authors) > 0 {
+			return
+		}
+		seen := make(map[string]bool)
+		for _, name := range names {
+			name = strings.Join(strings.Fields(name), " ")
+			if name == "" || seen[strings.ToLower(name)] || len(article.Authors) >= maxAuthors {
+				continue
+			}
+			// profile URLs, as article:author often holds, are not names
+			if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			article.Authors = append(article.Authors, name)
+		}
+		if len(article.Authors) > 0 {
+			article.AuthorsSource = from
+		}
+	}
+
+	// JSON-LD: the article objects first, then anything with a date
+	var objects []map[string]any
+	for _, raw := range result.SchemaOrg {
+		var data any
+		if err := json.Unmarshal([]byte(raw), &data); err == nil {
+			objects = append(objects, schemaObjects(data)...)
+		}
+	}
+	for _, articlesOnly := range []bool{true, false} {
+		for _, object := range objects {
+			if articlesOnly && !isArticleObject(object) {
+				continue
+			}
+			setDate(&article.PublishedAt, &article.PublishedSource, schemaText(object["datePublished"]), entity.ArticleSourceJSONLD)
+			setDate(&article.ModifiedAt, &article.ModifiedSource, schemaText(object["dateModified"]), entity.ArticleSourceJSONLD)
+			if articlesOnly {
+				setAuthors(schemaNames(object["author"]), entity.ArticleSourceJSONLD)
+			}
+		}
+	}
+
+	meta := make(map[string][]string)
+	var microPublished, microModified, timeValues, bylines []string
+	var microAuthors []string
+	var pubdateTime string
+	uc.traverseNode(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode {
+			return
+		}
+		if node.Data == "meta" {
+			key := strings.ToLower(htmlAttr(node, "property"))
+			if key == "" {
+				key = strings.ToLower(htmlAttr(node, "name"))
+			}
+			if key == "" {
+				key = strings.ToLower(htmlAttr(node, "http-equiv"))
+			}
+			if content := strings.TrimSpace(htmlAttr(node, "content")); key != "" && content != "" {
+				meta[key] = append(meta[key], content)
+			}
+		}
+
+		switch strings.ToLower(htmlAttr(node, "itemprop")) {
+		case "datepublished":
+			microPublished = append(microPublished, uc.microdataValue(node))
+		case "datemodified":
+			microModified = append(microModified, uc.microdataValue(node))
+		case "author", "creator":
+			microAuthors = append(microAuthors, uc.microdataName(node))
+		}
+
+		if node.Data == "time" {
+			if value := htmlAttr(node, "datetime"); value != "" {
+				if hasAttr(node, "pubdate") && pubdateTime == "" {
+					pubdateTime = value
+				}
+				// a <time> outside the content is more likely an event or
+				// a widget than the publication date
+				if pageRegion(node) == entity.RegionMain {
+					timeValues = append(timeValues, value)
+				}
+			}
+		}
+
+		if strings.EqualFold(htmlAttr(node, "rel"), "author") || isBylineNode(node) {
+			if text := uc.getTextContent(node); text != "" && len(text) <= 120 {
+				bylines = append(bylines, text)
+			}
+		}
+	})
+
+	for _, key := range publishedMeta {
+		for _, value := range meta[key] {
+			setDate(&article.PublishedAt, &article.PublishedSource, value, entity.ArticleSourceMeta)
+		}
+	}
+	for _, key := range modifiedMeta {
+		for _, value := range meta[key] {
+			setDate(&article.ModifiedAt, &article.ModifiedSource, value, entity.ArticleSourceMeta)
+		}
+	}
+	for _, key := range authorMeta {
+		setAuthors(meta[key], entity.ArticleSourceMeta)
+	}
+
+	for _, value := range microPublished {
+		setDate(&article.PublishedAt, &article.PublishedSource, value, entity.ArticleSourceMicrodata)
+	}
+	for _, value := range microModified {
+		setDate(&article.ModifiedAt, &article.ModifiedSource, value, entity.ArticleSourceMicrodata)
+	}
+	setAuthors(microAuthors, entity.ArticleSourceMicrodata)
+
+	if pubdateTime != "" {
+		setDate(&article.PublishedAt, &article.PublishedSource, pubdateTime, entity.ArticleSourceTime)
+	}
+	for _, value := range timeValues {
+		setDate(&article.PublishedAt, &article.PublishedSource, value, entity.ArticleSourceTime)
+	}
+
+	var names []string
+	for _, byline := range bylines {
+		// "By Jane Doe | 3 March 2024": the date after the separator goes
+		if i := strings.IndexAny(byline, "|•·—"); i >= 0 {
+			byline = byline[:i]
+		}
+		byline = bylinePrefix.ReplaceAllString(strings.TrimSpace(byline), "")
+		for _, name := range authorSplit.Split(byline, -1) {
+			// a byline longer than a few words is a sentence, not a name
+			if words := len(strings.Fields(name)); words > 0 && words <= 5 && !strings.ContainsAny(name, "0123456789") {
+				names = append(names, name)
+			}
+		}
+	}
+	setAuthors(names, entity.ArticleSourceByline)
+
+	if article.PublishedAt == nil {
+		if u, err := url.Parse(pageURL); err == nil {
+			if t, ok := dateFromPath(u.Path); ok {
+				article.PublishedAt = &t
+				article.PublishedSource = entity.ArticleSourceURL
+			}
+		}
+	}
+
+	if result.Author == "" && len(article.Authors) > 0 {
+		result.Author = strings.Join(article.Authors, ", ")
+	}
+}
+
+// schemaObjects flattens a JSON-LD document into its objects, @graph
+// members and nested values included.
+func schemaObjects(v any) []map[string]any {
+	var out []map[string]any
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			out = append(out, schemaObjects(item)...)
+		}
+	case map[string]any:
+		out = append(out, v)
+		for _, key := range sortedKeys(v) {
+			out = append(out, schemaObjects(v[key])...)
+		}
+	}
+	return out
+}
+
+func isArticleObject(object map[string]any) bool {
+	for _, t := range schemaStrings(object["@type"]) {
+		t = strings.TrimPrefix(strings.TrimPrefix(t, "https://schema.org/"), "http://schema.org/")
+		if articleTypes[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaNames reads an author property: a name, a Person or Organization,
+// or a list of them.
+func schemaNames(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		if name := schemaText(v["name"]); name != "" {
+			return []string{name}
+		}
+	case []any:
+		var out []string
+		for _, item := range v {
+			out = append(out, schemaNames(item)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// microdataValue is the value of an itemprop element: its content or
+// datetime attribute, or its text.
+func (uc *ScrapingUseCase) microdataValue(node *html.Node) string {
+	if value := htmlAttr(node, "content"); value != "" {
+		return value
+	}
+	if value := htmlAttr(node, "datetime"); value != "" {
+		return value
+	}
+	return uc.getTextContent(node)
+}
+
+// microdataName is the name of an itemprop=author element, which is either
+// the text itself or a Person with its own itemprop=name.
+func (uc *ScrapingUseCase) microdataName(node *html.Node) string {
+	if hasAttr(node, "itemscope") {
+		var name string
+		uc.traverseNode(node, func(child *html.Node) {
+			if name == "" && child != node && child.Type == html.ElementNode && strings.EqualFold(htmlAttr(child, "itemprop"), "name") {
+				name = uc.microdataValue(child)
+			}
+		})
+		return name
+	}
+	return uc.microdataValue(node)
+}
+
+// isBylineNode tells the elements whose class marks them as a byline or an
+// author name.
+func isBylineNode(node *html.Node) bool {
+	for _, class := range strings.Fields(strings.ToLower(htmlAttr(node, "class"))) {
+		switch class {
+		case "byline", "author", "author-name", "post-author", "entry-author", "article-author", "byline-author":
+			return true
+		}
+	}
+	return false
+}
+
+// dateFromPath reads the publication date that news and blog URLs often
+// carry, such as /2024/03/15/slug. A month without a day counts as the
+// first of the month.
+func dateFromPath(path string) (time.Time, bool) {
+	m := urlDatePattern.FindStringSubmatch(path)
+	if m == nil {
+		return time.Time{}, false
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day := 1
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day || t.After(time.Now().AddDate(0, 0, 1)) {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// limitedBody reads at most limit bytes of a response body and records
+// whether the body went on past the limit.
+type limitedBody struct {
+	r         io.Reader
+	remaining int64
+	n         int64
+	truncated bool
+}
+
+func newLimitedBody(r io.Reader, limit int64) *limitedBody {
+	return &limitedBody{r: r, remaining: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// probe one byte to tell an exact fit from a truncated body
+		var probe [1]byte
+		if n, _ := io.ReadFull(b.r, probe[:]); n > 0 {
+			b.truncated = true
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// sniffHTML decides whether a response should be parsed as HTML. The
+// declared content type wins; without one the first bytes are sniffed. The
+// returned reader replays the sniffed bytes.
+func sniffHTML(body io.Reader, contentType string) (io.Reader, bool) {
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err == nil {
+			return body, isHTMLMediaType(mediaType)
+		}
+	}
+	br := bufio.NewReaderSize(body, 512)
+	head, _ := br.Peek(512)
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return br, isHTMLMediaType(mediaType)
+}
+
+func isHTMLMediaType(mediaType string) bool {
+	switch strings.ToLower(mediaType) {
+	case "text/html", "application/xhtml+xml":
+		return true
+	}
+	return false
+}
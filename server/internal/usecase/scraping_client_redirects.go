@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+// jsRedirectPattern matches the obvious JavaScript redirects: assignments to
+// location or location.href and calls to location.replace or assign, with a
+// literal URL. location is either a property of window, document, top or
+// self or a bare name, not a property of another object; the first group
+// keeps what precedes it so that declarations can be told apart.
+var jsRedirectPattern = regexp.MustCompile(
+	`(^|[^\w$.])(?:(?:window|document|top|self)\.)?location(?:\.href)?\s*=\s*["']([^"']+)["']` +
+		`|(^|[^\w$.])(?:(?:window|document|top|self)\.)?location\.(?:replace|assign)\(\s*["']([^"']+)["']\s*\)`)
+
+// declarationPattern matches the end of `var `, `let ` or `const `, before a
+// variable of its own called location.
+var declarationPattern = regexp.MustCompile(`\b(?:var|let|const)\s*$`)
+
+// extractClientRedirects records the meta refresh and inline JavaScript
+// redirects of the page, resolved against its final URL, and flags each of
+// them as an SEO issue.
+func (uc *ScrapingUseCase) extractClientRedirects(doc *html.Node, result *entity.ScrapingResult, pageURL string) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	add := func(redirectType, target string, delay int) {
+		resolved, err := base.Parse(strings.TrimSpace(target))
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return
+		}
+		resolved.Fragment = ""
+		self := *base
+		self.Fragment = ""
+		if resolved.String() == self.String() {
+			// a refresh of the page itself is not a redirect
+			return
+		}
+		redirect := entity.ClientRedirect{Type: redirectType, Target: resolved.String(), Delay: delay}
+		result.ClientRedirects = append(result.ClientRedirects, redirect)
+		result.SEOIssues = append(result.SEOIssues, clientRedirectIssue(redirect))
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "meta":
+				if strings.EqualFold(htmlAttr(n, "http-equiv"), "refresh") {
+					if delay, target, ok := parseMetaRefresh(htmlAttr(n, "content")); ok {
+						add(entity.RedirectMetaRefresh, target, delay)
+					}
+				}
+			case "script":
+				if htmlAttr(n, "src") == "" && n.FirstChild != nil {
+					script := n.FirstChild.Data
+					for _, m := range jsRedirectPattern.FindAllStringSubmatchIndex(script, -1) {
+						// m[2:6] are the prefix and URL of an assignment,
+						// m[6:10] those of a replace or assign call
+						prefixEnd, target := m[3], ""
+						if m[4] >= 0 {
+							target = script[m[4]:m[5]]
+						} else {
+							prefixEnd, target = m[7], script[m[8]:m[9]]
+						}
+						if declarationPattern.MatchString(script[:prefixEnd]) {
+							continue
+						}
+						add(entity.RedirectJavaScript, target, 0)
+					}
+				}
+			case "noscript", "template":
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+}
+
+// parseMetaRefresh splits a refresh content such as `5; url=/next` into its
+// delay and target. A refresh without a URL only reloads the page.
+func parseMetaRefresh(content string) (int, string, bool) {
+	// "5, url=/next" is also seen in the wild
+	delayPart, rest := content, ""
+	if i := strings.IndexAny(content, ";,"); i >= 0 {
+		delayPart, rest = content[:i], content[i+1:]
+	}
+	delay, err := strconv.ParseFloat(strings.TrimSpace(delayPart), 64)
+	if err != nil || delay < 0 {
+		return 0, "", false
+	}
+
+	rest = strings.TrimSpace(rest)
+	if len(rest) >= 4 && strings.EqualFold(rest[:3], "url") {
+		if eq := strings.TrimSpace(rest[3:]); strings.HasPrefix(eq, "=") {
+			rest = strings.TrimSpace(eq[1:])
+		}
+	}
+	rest = strings.Trim(rest, `"'`)
+	if rest == "" {
+		return 0, "", false
+	}
+	return int(delay), rest, true
+}
+
+func clientRedirectIssue(redirect entity.ClientRedirect) entity.SEOIssue {
+	kind := "meta refresh"
+	if redirect.Type == entity.RedirectJavaScript {
+		kind = "JavaScript"
+	}
+	message := fmt.Sprintf("%s redirect to %s; use a 301 redirect instead", kind, redirect.Target)
+	if redirect.Delay > 0 {
+		message = fmt.Sprintf("%s redirect to %s after %d seconds; use a 301 redirect instead", kind, redirect.Target, redirect.Delay)
+	}
+	return entity.SEOIssue{Code: entity.SEOClientSideRedirect, Message: message}
+}
+
+// nextClientRedirect returns the first redirect of result to a page not yet
+// visited, or nil.
+func nextClientRedirect(result *entity.ScrapingResult, visited map[string]bool) *entity.ClientRedirect {
+	for i := range result.ClientRedirects {
+		if !visited[result.ClientRedirects[i].Target] {
+			return &result.ClientRedirects[i]
+		}
+	}
+	return nil
+}
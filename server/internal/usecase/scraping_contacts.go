@@ -0,0 +1,679 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+// maxContacts caps each list of the contacts section.
+const maxContacts = 100
+
+var (
+	emailPattern     = regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,24}`)
+	obfuscatedAt     = regexp.MustCompile(`(?i)\s*[\[({<]\s*(?:at|arroba)\s*[\])}>]\s*`)
+	obfuscatedDot    = regexp.MustCompile(`(?i)\s*[\[({<]\s*(?:dot|punto)\s*[\])}>]\s*`)
+	phonePattern     = regexp.MustCompile(`(?:\+|\b00)\d[\d\s().\-/]{6,20}\d`)
+	labelledPhone    = regexp.MustCompile(`(?i)\b(?:tel|tlf|tel[eé]fono|telephone|phone|m[oó]vil|mobile)\b\.?\s*:?\s*(\+?\(?\d[\d\s().\-/]{5,20}\d)`)
+	trunkZeroPattern = regexp.MustCompile(`\(0\)`)
+	twitterHandle    = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+)
+
+// callingCodes are the country calling codes used to turn national numbers
+// into E.164 when the country of the page is known.
+var callingCodes = map[string]string{
+	"US": "1", "CA": "1", "GB": "44", "IE": "353", "ES": "34", "PT": "351",
+	"FR": "33", "DE": "49", "AT": "43", "CH": "41", "IT": "39", "NL": "31",
+	"BE": "32", "LU": "352", "DK": "45", "SE": "46", "NO": "47", "FI": "358",
+	"PL": "48", "CZ": "420", "GR": "30", "MX": "52", "AR": "54", "BR": "55",
+	"CL": "56", "CO": "57", "PE": "51", "AU": "61", "NZ": "64", "IN": "91",
+	"JP": "81",
+}
+
+// contactCollector deduplicates the contacts of a page as they are found
+// and merges the places each one was found in.
+type contactCollector struct {
+	contacts  *entity.Contacts
+	country   string
+	emails    map[string]int
+	phones    map[string]int
+	addresses map[string]int
+	profiles  map[string]int
+}
+
+// extractContacts gathers the email addresses, phone numbers, postal
+// addresses and social profiles of the page: mailto: and tel: links, the
+// text (including "name [at] domain [dot] com" and Cloudflare-protected
+// addresses), schema.org JSON-LD and microdata, and the links extracted.
+func (uc *ScrapingUseCase) extractContacts(doc *html.Node, result *entity.ScrapingResult, pageURL string) {
+	c := &contactCollector{
+		contacts:  &result.Contacts,
+		emails:    make(map[string]int),
+		phones:    make(map[string]int),
+		addresses: make(map[string]int),
+		profiles:  make(map[string]int),
+	}
+	result.Contacts = entity.Contacts{
+		Emails:         []entity.ContactEmail{},
+		Phones:         []entity.ContactPhone{},
+		Addresses:      []entity.PostalAddress{},
+		SocialProfiles: []entity.SocialProfile{},
+	}
+
+	// addresses come first: their country tells how to read national
+	// phone numbers
+	schemaSource := entity.ContactSource{Type: entity.ContactSourceSchemaOrg}
+	var schemaDocs []any
+	for _, raw := range result.SchemaOrg {
+		var data any
+		if err := json.Unmarshal([]byte(raw), &data); err == nil {
+			schemaDocs = append(schemaDocs, data)
+			c.walkSchemaAddresses(data, schemaSource)
+		}
+	}
+	uc.traverseNode(doc, func(node *html.Node) {
+		if node.Type == html.ElementNode && strings.HasSuffix(strings.ToLower(htmlAttr(node, "itemtype")), "schema.org/postaladdress") {
+			c.addAddress(microdataAddress(node), entity.ContactSource{Type: entity.ContactSourceMicrodata, Region: pageRegion(node)})
+		}
+	})
+	c.country = contactCountry(doc, result, pageURL)
+
+	for _, data := range schemaDocs {
+		c.walkSchema(data, schemaSource)
+	}
+
+	uc.traverseNode(doc, func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			if parent := node.Parent; parent != nil {
+				switch parent.Data {
+				case "script", "style", "noscript", "template", "textarea":
+					return
+				}
+			}
+			c.scanText(node.Data, pageRegion(node))
+		case html.ElementNode:
+			region := pageRegion(node)
+			if encoded := htmlAttr(node, "data-cfemail"); encoded != "" {
+				c.addEmail(decodeCFEmail(encoded), entity.ContactSource{Type: entity.ContactSourceObfuscated, Region: region})
+			}
+			if itemprop := strings.ToLower(htmlAttr(node, "itemprop")); itemprop == "telephone" || itemprop == "email" {
+				value := htmlAttr(node, "content")
+				if value == "" {
+					value = uc.getTextContent(node)
+				}
+				source := entity.ContactSource{Type: entity.ContactSourceMicrodata, Region: region}
+				if itemprop == "telephone" {
+					c.addPhone(value, source)
+				} else {
+					c.addEmail(strings.TrimPrefix(value, "mailto:"), source)
+				}
+			}
+			if node.Data != "a" && node.Data != "area" {
+				return
+			}
+			href := strings.TrimSpace(htmlAttr(node, "href"))
+			lower := strings.ToLower(href)
+			switch {
+			case strings.HasPrefix(lower, "mailto:"):
+				for _, address := range mailtoAddresses(href) {
+					c.addEmail(address, entity.ContactSource{Type: entity.ContactSourceMailto, Region: region})
+				}
+			case strings.HasPrefix(lower, "tel:"):
+				number := href[len("tel:"):]
+				if unescaped, err := url.PathUnescape(number); err == nil {
+					number = unescaped
+				}
+				number, _, _ = strings.Cut(number, ";")
+				c.addPhone(number, entity.ContactSource{Type: entity.ContactSourceTel, Region: region})
+			case strings.Contains(lower, "/cdn-cgi/l/email-protection#"):
+				_, encoded, _ := strings.Cut(href, "#")
+				c.addEmail(decodeCFEmail(encoded), entity.ContactSource{Type: entity.ContactSourceObfuscated, Region: region})
+			default:
+				// anchors past the max_links cap still count
+				if resolved := uc.resolveURL(pageURL, href); resolved != "" {
+					c.addProfile(resolved, entity.ContactSource{Type: entity.ContactSourceLink, Region: region})
+				}
+			}
+		}
+	})
+
+	for _, link := range result.Links {
+		c.addProfile(link.URL, entity.ContactSource{Type: entity.ContactSourceLink, Region: link.Region})
+	}
+}
+
+// scanText looks for email addresses and phone numbers in a text node.
+// Phone numbers in plain text need an international prefix or a label
+// such as "Tel:", since any run of digits could be one.
+func (c *contactCollector) scanText(text, region string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	textSource := entity.ContactSource{Type: entity.ContactSourceText, Region: region}
+	found := make(map[string]bool)
+	for _, address := range emailPattern.FindAllString(text, -1) {
+		found[strings.ToLower(address)] = true
+		c.addEmail(address, textSource)
+	}
+	if deobfuscated := obfuscatedDot.ReplaceAllString(obfuscatedAt.ReplaceAllString(text, "@"), "."); deobfuscated != text {
+		for _, address := range emailPattern.FindAllString(deobfuscated, -1) {
+			if !found[strings.ToLower(address)] {
+				c.addEmail(address, entity.ContactSource{Type: entity.ContactSourceObfuscated, Region: region})
+			}
+		}
+	}
+
+	for _, number := range phonePattern.FindAllString(text, -1) {
+		c.addPhone(number, textSource)
+	}
+	for _, m := range labelledPhone.FindAllStringSubmatch(text, -1) {
+		c.addPhone(m[1], textSource)
+	}
+}
+
+func (c *contactCollector) addEmail(address string, source entity.ContactSource) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if address == "" || emailPattern.FindString(address) != address {
+		return
+	}
+	// retina image names such as logo@2x.png look like addresses
+	switch address[strings.LastIndex(address, ".")+1:] {
+	case "png", "jpg", "jpeg", "gif", "svg", "webp", "avif":
+		return
+	}
+	if i, ok := c.emails[address]; ok {
+		c.contacts.Emails[i].Sources = addContactSource(c.contacts.Emails[i].Sources, source)
+		return
+	}
+	if len(c.contacts.Emails) >= maxContacts {
+		return
+	}
+	c.emails[address] = len(c.contacts.Emails)
+	c.contacts.Emails = append(c.contacts.Emails, entity.ContactEmail{
+		Address: address,
+		Sources: []entity.ContactSource{source},
+	})
+}
+
+func (c *contactCollector) addPhone(number string, source entity.ContactSource) {
+	number = strings.Join(strings.Fields(number), " ")
+	digits := phoneDigits(number)
+	if len(digits) < 7 || len(digits) > 15 {
+		return
+	}
+	e164 := toE164(number, c.country)
+	key := e164
+	if key == "" {
+		key = digits
+	}
+	if i, ok := c.phones[key]; ok {
+		c.contacts.Phones[i].Sources = addContactSource(c.contacts.Phones[i].Sources, source)
+		return
+	}
+	if len(c.contacts.Phones) >= maxContacts {
+		return
+	}
+	c.phones[key] = len(c.contacts.Phones)
+	c.contacts.Phones = append(c.contacts.Phones, entity.ContactPhone{
+		Number:  number,
+		E164:    e164,
+		Sources: []entity.ContactSource{source},
+	})
+}
+
+func (c *contactCollector) addAddress(address entity.PostalAddress, source entity.ContactSource) {
+	key := strings.ToLower(strings.Join([]string{
+		address.StreetAddress, address.Locality, address.Region,
+		address.PostalCode, address.Country, address.PostOfficeBox,
+	}, "|"))
+	if strings.Trim(key, "|") == "" {
+		return
+	}
+	if i, ok := c.addresses[key]; ok {
+		c.contacts.Addresses[i].Sources = addContactSource(c.contacts.Addresses[i].Sources, source)
+		return
+	}
+	if len(c.contacts.Addresses) >= maxContacts {
+		return
+	}
+	address.Sources = []entity.ContactSource{source}
+	c.addresses[key] = len(c.contacts.Addresses)
+	c.contacts.Addresses = append(c.contacts.Addresses, address)
+}
+
+func (c *contactCollector) addProfile(rawURL string, source entity.ContactSource) {
+	network, profileURL, ok := socialProfile(rawURL)
+	if !ok {
+		return
+	}
+	key := strings.ToLower(profileURL)
+	if i, ok := c.profiles[key]; ok {
+		c.contacts.SocialProfiles[i].Sources = addContactSource(c.contacts.SocialProfiles[i].Sources, source)
+		return
+	}
+	if len(c.contacts.SocialProfiles) >= maxContacts {
+		return
+	}
+	c.profiles[key] = len(c.contacts.SocialProfiles)
+	c.contacts.SocialProfiles = append(c.contacts.SocialProfiles, entity.SocialProfile{
+		Network: network,
+		URL:     profileURL,
+		Sources: []entity.ContactSource{source},
+	})
+}
+
+func addContactSource(sources []entity.ContactSource, source entity.ContactSource) []entity.ContactSource {
+	for _, s := range sources {
+		if s == source {
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
+// walkSchemaAddresses collects the PostalAddress objects of a JSON-LD
+// document, and the plain-text "address" properties.
+func (c *contactCollector) walkSchemaAddresses(v any, source entity.ContactSource) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			c.walkSchemaAddresses(item, source)
+		}
+	case map[string]any:
+		if schemaIsType(v, "PostalAddress") || v["streetAddress"] != nil {
+			c.addAddress(entity.PostalAddress{
+				StreetAddress: schemaText(v["streetAddress"]),
+				Locality:      schemaText(v["addressLocality"]),
+				Region:        schemaText(v["addressRegion"]),
+				PostalCode:    schemaText(v["postalCode"]),
+				Country:       schemaText(v["addressCountry"]),
+				PostOfficeBox: schemaText(v["postOfficeBoxNumber"]),
+			}, source)
+			return
+		}
+		if address, ok := v["address"].(string); ok {
+			c.addAddress(entity.PostalAddress{StreetAddress: strings.TrimSpace(address)}, source)
+		}
+		for _, key := range sortedKeys(v) {
+			c.walkSchemaAddresses(v[key], source)
+		}
+	}
+}
+
+// walkSchema collects the telephone, email and sameAs properties of a
+// JSON-LD document.
+func (c *contactCollector) walkSchema(v any, source entity.ContactSource) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			c.walkSchema(item, source)
+		}
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			switch key {
+			case "telephone":
+				for _, number := range schemaStrings(v[key]) {
+					c.addPhone(number, source)
+				}
+			case "email":
+				for _, address := range schemaStrings(v[key]) {
+					c.addEmail(strings.TrimPrefix(address, "mailto:"), source)
+				}
+			case "sameAs":
+				for _, profile := range schemaStrings(v[key]) {
+					c.addProfile(profile, source)
+				}
+			default:
+				c.walkSchema(v[key], source)
+			}
+		}
+	}
+}
+
+func schemaIsType(v map[string]any, name string) bool {
+	for _, t := range schemaStrings(v["@type"]) {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimPrefix(t, "https://schema.org/"), "http://schema.org/"), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaText reads a property that is either text or an object with a name,
+// as addressCountry often is.
+func schemaText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		return schemaText(v["name"])
+	case []any:
+		if len(v) > 0 {
+			return schemaText(v[0])
+		}
+	}
+	return ""
+}
+
+func schemaStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// microdataAddress reads the itemprop children of an itemtype=PostalAddress
+// element.
+func microdataAddress(n *html.Node) entity.PostalAddress {
+	var address entity.PostalAddress
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			value := htmlAttr(child, "content")
+			if value == "" {
+				var sb strings.Builder
+				var text func(*html.Node)
+				text = func(t *html.Node) {
+					if t.Type == html.TextNode {
+						sb.WriteString(t.Data)
+					}
+					for c := t.FirstChild; c != nil; c = c.NextSibling {
+						text(c)
+					}
+				}
+				text(child)
+				value = strings.Join(strings.Fields(sb.String()), " ")
+			}
+			switch htmlAttr(child, "itemprop") {
+			case "streetAddress":
+				address.StreetAddress = value
+			case "addressLocality":
+				address.Locality = value
+			case "addressRegion":
+				address.Region = value
+			case "postalCode":
+				address.PostalCode = value
+			case "addressCountry":
+				address.Country = value
+			case "postOfficeBoxNumber":
+				address.PostOfficeBox = value
+			default:
+				walk(child)
+			}
+		}
+	}
+	walk(n)
+	return address
+}
+
+// mailtoAddresses returns the recipients of a mailto: link, which may list
+// several, comma-separated, before the query.
+func mailtoAddresses(href string) []string {
+	to, _, _ := strings.Cut(href[len("mailto:"):], "?")
+	if unescaped, err := url.PathUnescape(to); err == nil {
+		to = unescaped
+	}
+	return strings.Split(to, ",")
+}
+
+// decodeCFEmail decodes an address hidden by Cloudflare's email protection:
+// hex bytes XORed with the first one.
+func decodeCFEmail(encoded string) string {
+	data, err := hex.DecodeString(encoded)
+	if err != nil || len(data) < 2 {
+		return ""
+	}
+	out := make([]byte, len(data)-1)
+	for i, b := range data[1:] {
+		out[i] = b ^ data[0]
+	}
+	return string(out)
+}
+
+// pageRegion names the region of the page a node sits in, from the nearest
+// landmark element or ARIA role around it. A header or footer only counts
+// as the page's own outside of articles and other sections.
+func pageRegion(n *html.Node) string {
+	for p := n; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(htmlAttr(p, "role")) {
+		case "navigation":
+			return entity.RegionNavigation
+		case "banner":
+			return entity.RegionHeader
+		case "contentinfo":
+			return entity.RegionFooter
+		case "main":
+			return entity.RegionMain
+		case "complementary":
+			return entity.RegionAside
+		}
+		switch p.Data {
+		case "nav":
+			return entity.RegionNavigation
+		case "main", "article":
+			return entity.RegionMain
+		case "aside":
+			return entity.RegionAside
+		case "header", "footer":
+			if !inSection(p) {
+				if p.Data == "header" {
+					return entity.RegionHeader
+				}
+				return entity.RegionFooter
+			}
+		}
+	}
+	return entity.RegionBody
+}
+
+func inSection(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode {
+			switch p.Data {
+			case "article", "section", "aside", "nav", "main":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contactCountry guesses the country of the page, to read national phone
+// numbers: from a schema.org address, the region of the page language or
+// the country-code TLD.
+func contactCountry(doc *html.Node, result *entity.ScrapingResult, pageURL string) string {
+	for _, address := range result.Contacts.Addresses {
+		if country := strings.ToUpper(address.Country); callingCodes[country] != "" {
+			return country
+		}
+	}
+
+	languages := []string{result.Language}
+	if root := findElement(doc, "html"); root != nil {
+		languages = append([]string{htmlAttr(root, "lang")}, languages...)
+	}
+	for _, language := range languages {
+		if _, region, ok := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-"); ok {
+			if country := strings.ToUpper(region); callingCodes[country] != "" {
+				return country
+			}
+		}
+	}
+
+	if u, err := url.Parse(pageURL); err == nil {
+		host := u.Hostname()
+		tld := strings.ToUpper(host[strings.LastIndex(host, ".")+1:])
+		if tld == "UK" {
+			tld = "GB"
+		}
+		if callingCodes[tld] != "" {
+			return tld
+		}
+	}
+	return ""
+}
+
+func phoneDigits(number string) string {
+	number = trunkZeroPattern.ReplaceAllString(number, "")
+	var sb strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// toE164 normalises a phone number to E.164. Numbers with a "+" or "00"
+// prefix carry their country; national ones are read with the country of
+// the page, dropping the trunk prefix. It returns "" when it can't tell.
+func toE164(number, country string) string {
+	number = strings.TrimSpace(number)
+	digits := phoneDigits(number)
+	var e164 string
+	switch {
+	case strings.HasPrefix(number, "+"):
+		e164 = digits
+	case strings.HasPrefix(digits, "00"):
+		e164 = digits[2:]
+	case callingCodes[country] != "":
+		national := digits
+		switch country {
+		case "US", "CA":
+			if len(national) == 11 && national[0] == '1' {
+				national = national[1:]
+			}
+		case "IT":
+			// Italian numbers keep their leading 0
+		default:
+			national = strings.TrimPrefix(national, "0")
+		}
+		e164 = callingCodes[country] + national
+	default:
+		return ""
+	}
+	if len(e164) < 8 || len(e164) > 15 || e164[0] == '0' {
+		return ""
+	}
+	return "+" + e164
+}
+
+// socialProfile tells whether rawURL is a profile on one of the social
+// networks tracked and returns its normalised URL. Share buttons, posts
+// and other non-profile pages are left out.
+func socialProfile(rawURL string) (string, string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"www.", "m.", "mobile."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	if strings.HasSuffix(host, ".linkedin.com") {
+		host = "linkedin.com"
+	}
+	var segments []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return "", "", false
+	}
+	first := strings.ToLower(segments[0])
+
+	switch host {
+	case "linkedin.com":
+		switch first {
+		case "in", "company", "school", "showcase":
+			if len(segments) >= 2 {
+				return entity.SocialLinkedIn, "https://www.linkedin.com/" + first + "/" + segments[1], true
+			}
+		}
+	case "twitter.com", "x.com":
+		switch first {
+		case "intent", "share", "home", "hashtag", "search", "i", "explore", "settings", "login", "signup", "messages", "notifications", "tos", "privacy":
+			return "", "", false
+		}
+		if twitterHandle.MatchString(segments[0]) {
+			return entity.SocialX, "https://x.com/" + segments[0], true
+		}
+	case "facebook.com", "fb.com":
+		switch first {
+		case "sharer", "sharer.php", "share", "share.php", "dialog", "plugins", "tr", "login", "login.php", "hashtag", "watch", "events", "photo.php", "photo", "story.php", "permalink.php", "policies", "help":
+			return "", "", false
+		case "profile.php":
+			if id := u.Query().Get("id"); id != "" {
+				return entity.SocialFacebook, "https://www.facebook.com/profile.php?id=" + url.QueryEscape(id), true
+			}
+			return "", "", false
+		case "pages", "groups":
+			if len(segments) >= 2 {
+				return entity.SocialFacebook, "https://www.facebook.com/" + strings.Join(segments, "/"), true
+			}
+			return "", "", false
+		}
+		return entity.SocialFacebook, "https://www.facebook.com/" + segments[0], true
+	case "instagram.com":
+		switch first {
+		case "p", "reel", "reels", "explore", "stories", "accounts", "tv", "direct":
+			return "", "", false
+		}
+		return entity.SocialInstagram, "https://www.instagram.com/" + segments[0], true
+	case "youtube.com":
+		if strings.HasPrefix(segments[0], "@") {
+			return entity.SocialYouTube, "https://www.youtube.com/" + segments[0], true
+		}
+		switch first {
+		case "channel", "c", "user":
+			if len(segments) >= 2 {
+				return entity.SocialYouTube, "https://www.youtube.com/" + first + "/" + segments[1], true
+			}
+		}
+	case "github.com":
+		switch first {
+		case "features", "about", "pricing", "login", "join", "marketplace", "explore", "topics", "sponsors", "settings", "search", "enterprise", "security", "site", "contact", "collections", "trending":
+			return "", "", false
+		case "orgs":
+			if len(segments) >= 2 {
+				return entity.SocialGitHub, "https://github.com/" + segments[1], true
+			}
+			return "", "", false
+		}
+		return entity.SocialGitHub, "https://github.com/" + segments[0], true
+	}
+	return "", "", false
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxForms      = 50
+	maxFormFields = 100
+)
+
+// csrfFieldPattern matches the names frameworks give their anti-CSRF
+// hidden fields: csrf_token, _token, authenticity_token,
+// __RequestVerificationToken, csrfmiddlewaretoken, form_key, _wpnonce...
+var csrfFieldPattern = regexp.MustCompile(`(?i)csrf|xsrf|^_token$|authenticity_token|requestverificationtoken|^form_?key$|nonce`)
+
+// captchaClasses are the classes of the captcha widgets.
+var captchaClasses = map[string]string{
+	"g-recaptcha":  "recaptcha",
+	"h-captcha":    "hcaptcha",
+	"cf-turnstile": "turnstile",
+	"frc-captcha":  "friendly_captcha",
+}
+
+// extractForms catalogues the forms of the page with their fields, the
+// anti-CSRF token and captcha they carry and their security issues:
+// passwords over HTTP or in GET requests, and actions that go to another
+// origin or downgrade to HTTP. Fields that name a form in their form
+// attribute belong to it, inside another form or outside any.
+func (uc *ScrapingUseCase) extractForms(doc *html.Node, result *entity.ScrapingResult, pageURL string) {
+	result.Forms = []entity.Form{}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	// invisible reCAPTCHA v3 is loaded with ?render= and has no widget
+	pageCaptcha := ""
+	byID := make(map[string]int)
+	var external []*html.Node
+	uc.traverseNode(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode {
+			return
+		}
+		switch node.Data {
+		case "script":
+			src := htmlAttr(node, "src")
+			if strings.Contains(src, "recaptcha/api.js") && strings.Contains(src, "render=") && !strings.Contains(src, "render=explicit") {
+				pageCaptcha = "recaptcha"
+			}
+		case "form":
+			if len(result.Forms) >= maxForms {
+				return
+			}
+			form := uc.parseForm(node, base)
+			if form.ID != "" {
+				byID[form.ID] = len(result.Forms)
+			}
+			result.Forms = append(result.Forms, form)
+		case "input", "select", "textarea":
+			// form="other" moves a field to another form, wherever it sits
+			if owner := htmlAttr(node, "form"); owner != "" {
+				if parent := enclosingForm(node); parent == nil || owner != htmlAttr(parent, "id") {
+					external = append(external, node)
+				}
+			}
+		}
+	})
+
+	for _, node := range external {
+		if i, ok := byID[htmlAttr(node, "form")]; ok {
+			addFormField(&result.Forms[i], node)
+		}
+	}
+
+	for i := range result.Forms {
+		form := &result.Forms[i]
+		if form.Captcha == "" {
+			form.Captcha = pageCaptcha
+		}
+		form.Issues = formIssues(form, base)
+	}
+}
+
+func (uc *ScrapingUseCase) parseForm(node *html.Node, base *url.URL) entity.Form {
+	form := entity.Form{
+		ID:           htmlAttr(node, "id"),
+		Name:         htmlAttr(node, "name"),
+		Action:       base.String(),
+		Method:       strings.ToUpper(strings.TrimSpace(htmlAttr(node, "method"))),
+		Enctype:      htmlAttr(node, "enctype"),
+		Autocomplete: htmlAttr(node, "autocomplete"),
+		Region:       pageRegion(node),
+		Fields:       []entity.FormField{},
+		Issues:       []string{},
+	}
+	if action := strings.TrimSpace(htmlAttr(node, "action")); action != "" {
+		if resolved, err := base.Parse(action); err == nil {
+			form.Action = resolved.String()
+		}
+	}
+	if form.Method != "POST" && form.Method != "DIALOG" {
+		form.Method = "GET"
+	}
+
+	uc.traverseNode(node, func(child *html.Node) {
+		if child == node || child.Type != html.ElementNode {
+			return
+		}
+		switch child.Data {
+		case "input", "select", "textarea":
+			// a field can opt out of its form with form="other"
+			if owner := htmlAttr(child, "form"); owner == "" || owner == form.ID {
+				addFormField(&form, child)
+			}
+		}
+		if form.Captcha == "" {
+			for _, class := range strings.Fields(htmlAttr(child, "class")) {
+				if captcha, ok := captchaClasses[class]; ok {
+					form.Captcha = captcha
+				}
+			}
+		}
+	})
+	return form
+}
+
+// addFormField records a field. Buttons are not fields; the hidden field
+// that looks like an anti-CSRF token is noted on the form.
+func addFormField(form *entity.Form, node *html.Node) {
+	fieldType := node.Data
+	if node.Data == "input" {
+		fieldType = strings.ToLower(strings.TrimSpace(htmlAttr(node, "type")))
+		if fieldType == "" {
+			fieldType = "text"
+		}
+	}
+	switch fieldType {
+	case "submit", "button", "reset", "image":
+		return
+	}
+
+	name := htmlAttr(node, "name")
+	if fieldType == "hidden" && form.CSRFField == "" && csrfFieldPattern.MatchString(name) {
+		form.CSRFField = name
+	}
+	switch name {
+	case "g-recaptcha-response":
+		form.Captcha = "recaptcha"
+	case "h-captcha-response":
+		form.Captcha = "hcaptcha"
+	case "cf-turnstile-response":
+		form.Captcha = "turnstile"
+	}
+	if len(form.Fields) >= maxFormFields {
+		return
+	}
+	form.Fields = append(form.Fields, entity.FormField{
+		Name:         name,
+		Type:         fieldType,
+		Required:     hasAttr(node, "required"),
+		Autocomplete: htmlAttr(node, "autocomplete"),
+	})
+}
+
+func enclosingForm(node *html.Node) *html.Node {
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "form" {
+			return p
+		}
+	}
+	return nil
+}
+
+// formIssues flags the insecure ways a form handles its data.
+func formIssues(form *entity.Form, page *url.URL) []string {
+	issues := []string{}
+	hasPassword := false
+	for _, field := range form.Fields {
+		if field.Type == "password" {
+			hasPassword = true
+		}
+	}
+	action, err := url.Parse(form.Action)
+	if err != nil {
+		return issues
+	}
+
+	if hasPassword && page.Scheme == "http" {
+		issues = append(issues, entity.FormPasswordOverHTTP)
+	}
+	origin := page
+	if page.Scheme == "https" && action.Scheme == "http" {
+		issues = append(issues, entity.FormInsecureAction)
+		// the downgrade is reported on its own, not as another origin too
+		downgraded := *page
+		downgraded.Scheme = "http"
+		origin = &downgraded
+	}
+	if (action.Scheme == "http" || action.Scheme == "https") && !sameOrigin(action, origin) {
+		issues = append(issues, entity.FormCrossOrigin)
+	}
+	if hasPassword && form.Method == "GET" {
+		issues = append(issues, entity.FormPasswordInGET)
+	}
+	return issues
+}
+
+// sameOrigin compares scheme, host and port, with the default ports made
+// explicit.
+func sameOrigin(a, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		if u.Scheme == "https" {
+			return "443"
+		}
+		return "80"
+	}
+	return a.Scheme == b.Scheme && strings.EqualFold(a.Hostname(), b.Hostname()) && port(a) == port(b)
+}
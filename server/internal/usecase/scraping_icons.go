@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+const maxManifestBytes = 512 * 1024
+
+// webManifest mirrors the subset of the W3C web app manifest we read.
+type webManifest struct {
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	Description     string `json:"description"`
+	ThemeColor      string `json:"theme_color"`
+	BackgroundColor string `json:"background_color"`
+	Display         string `json:"display"`
+	StartURL        string `json:"start_url"`
+	Icons           []struct {
+		Src     string `json:"src"`
+		Sizes   string `json:"sizes"`
+		Type    string `json:"type"`
+		Purpose string `json:"purpose"`
+	} `json:"icons"`
+}
+
+// extractFavicon discovers the icons declared by the page and its web app
+// manifest, and picks the best one for display. The conventional
+// /favicon.ico is only probed when nothing is declared.
+func (uc *ScrapingUseCase) extractFavicon(ctx context.Context, n *html.Node, result *entity.ScrapingResult, page *PageResponse) {
+	// Usar la URL final (post-redirect) para resolver rutas relativas
+	baseURL := result.URL
+	if result.FinalURL != "" {
+		baseURL = result.FinalURL
+	}
+
+	var manifestHref string
+	seen := make(map[string]bool)
+
+	uc.traverseNode(n, func(node *html.Node) {
+		if node.Type != html.ElementNode || node.Data != "link" {
+			return
+		}
+		var rel, href, sizes, typ, color string
+		for _, attr := range node.Attr {
+			switch attr.Key {
+			case "rel":
+				rel = strings.ToLower(strings.Join(strings.Fields(attr.Val), " "))
+			case "href":
+				href = strings.TrimSpace(attr.Val)
+			case "sizes":
+				sizes = strings.TrimSpace(attr.Val)
+			case "type":
+				typ = strings.TrimSpace(attr.Val)
+			case "color":
+				color = strings.TrimSpace(attr.Val)
+			}
+		}
+		if href == "" {
+			return
+		}
+		if rel == "manifest" {
+			if manifestHref == "" {
+				manifestHref = uc.resolveURL(baseURL, href)
+			}
+			return
+		}
+		if !isIconRel(rel) {
+			return
+		}
+		absoluteURL := uc.resolveURL(baseURL, href)
+		if absoluteURL == "" || seen[rel+" "+absoluteURL] {
+			return
+		}
+		seen[rel+" "+absoluteURL] = true
+
+		icon := entity.Icon{
+			URL:    absoluteURL,
+			Rel:    rel,
+			Sizes:  sizes,
+			Type:   typ,
+			Color:  color,
+			Source: "link",
+		}
+		icon.Width, icon.Height = parseIconSizes(sizes)
+		result.Icons = append(result.Icons, icon)
+	})
+
+	if manifestHref != "" {
+		if manifest := uc.fetchManifest(ctx, page, manifestHref); manifest != nil {
+			result.Manifest = manifest
+			result.Icons = append(result.Icons, manifest.Icons...)
+		}
+	}
+
+	if len(result.Icons) == 0 {
+		parsedURL, err := url.Parse(baseURL)
+		if err != nil {
+			return
+		}
+		defaultURL := fmt.Sprintf("%s://%s/favicon.ico", parsedURL.Scheme, parsedURL.Host)
+		if uc.checkURLExists(ctx, page, defaultURL) {
+			result.Icons = append(result.Icons, entity.Icon{
+				URL:    defaultURL,
+				Rel:    "icon",
+				Type:   "image/x-icon",
+				Source: "default",
+			})
+		}
+	}
+
+	if best := bestIcon(result.Icons); best != nil {
+		result.Favicon = best.URL
+	}
+}
+
+func (uc *ScrapingUseCase) fetchManifest(ctx context.Context, page *PageResponse, manifestURL string) *entity.WebManifest {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := page.NewRequest(ctx, "GET", manifestURL)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("Accept", "application/manifest+json,application/json;q=0.9,*/*;q=0.5")
+
+	resp, err := page.Client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var raw webManifest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(&raw); err != nil {
+		return nil
+	}
+
+	manifest := &entity.WebManifest{
+		URL:             manifestURL,
+		Name:            raw.Name,
+		ShortName:       raw.ShortName,
+		Description:     raw.Description,
+		ThemeColor:      raw.ThemeColor,
+		BackgroundColor: raw.BackgroundColor,
+		Display:         raw.Display,
+		Icons:           []entity.Icon{},
+	}
+	if raw.StartURL != "" {
+		manifest.StartURL = uc.resolveURL(manifestURL, raw.StartURL)
+	}
+	for _, ic := range raw.Icons {
+		src := strings.TrimSpace(ic.Src)
+		if src == "" {
+			continue
+		}
+		// Las rutas de los iconos del manifest son relativas al propio manifest
+		absoluteURL := uc.resolveURL(manifestURL, src)
+		if absoluteURL == "" {
+			continue
+		}
+		icon := entity.Icon{
+			URL:     absoluteURL,
+			Rel:     "manifest",
+			Sizes:   ic.Sizes,
+			Type:    ic.Type,
+			Purpose: ic.Purpose,
+			Source:  "manifest",
+		}
+		icon.Width, icon.Height = parseIconSizes(ic.Sizes)
+		manifest.Icons = append(manifest.Icons, icon)
+	}
+	return manifest
+}
+
+func isIconRel(rel string) bool {
+	switch rel {
+	case "icon", "shortcut icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon", "fluid-icon":
+		return true
+	}
+	for _, token := range strings.Fields(rel) {
+		if token == "icon" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIconSizes returns the largest WxH pair of a sizes attribute
+// ("16x16 32x32"). "any" and malformed values yield 0x0.
+func parseIconSizes(sizes string) (int, int) {
+	bestW, bestH := 0, 0
+	for _, size := range strings.Fields(strings.ToLower(sizes)) {
+		w, h, ok := strings.Cut(size, "x")
+		if !ok {
+			continue
+		}
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW != nil || errH != nil {
+			continue
+		}
+		if width*height > bestW*bestH {
+			bestW, bestH = width, height
+		}
+	}
+	return bestW, bestH
+}
+
+// bestIcon prefers scalable (SVG or sizes="any") full-colour icons, then
+// the largest declared size. Mask icons are monochrome and only used as a
+// last resort.
+func bestIcon(icons []entity.Icon) *entity.Icon {
+	var best *entity.Icon
+	bestScore := -1
+	for i := range icons {
+		if score := iconScore(icons[i]); score > bestScore {
+			best, bestScore = &icons[i], score
+		}
+	}
+	return best
+}
+
+func iconScore(icon entity.Icon) int {
+	if icon.Rel == "mask-icon" || strings.Contains(icon.Purpose, "monochrome") {
+		return 0
+	}
+	if strings.Contains(icon.Type, "svg") || strings.HasSuffix(strings.ToLower(icon.URL), ".svg") ||
+		strings.EqualFold(strings.TrimSpace(icon.Sizes), "any") {
+		return 10000
+	}
+	size := icon.Width
+	if size == 0 {
+		switch {
+		case strings.HasPrefix(icon.Rel, "apple-touch-icon"):
+			size = 180
+		default:
+			size = 16
+		}
+	}
+	if strings.Contains(icon.Purpose, "maskable") && !strings.Contains(icon.Purpose, "any") {
+		size /= 2
+	}
+	return size
+}
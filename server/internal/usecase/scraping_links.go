@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+// extractLinks collects the URLs a crawler finds on the page: <a> and
+// <area> first, so that they keep their place under maxLinks, then <link
+// rel=alternate|next|prev|amphtml>, iframes, form actions, srcset, inline
+// style url() and the HTTP Link header. Each link records where it comes
+// from, its nofollow, sponsored and ugc flags and the region of the page.
+// Every hyperlink to the site also goes to InternalLinks, which the link
+// graph reads and maxLinks does not cap.
+func (uc *ScrapingUseCase) extractLinks(n *html.Node, result *entity.ScrapingResult, baseURL string, header http.Header, maxLinks int) {
+	linkMap := make(map[string]bool)
+	baseParsed, _ := url.Parse(baseURL)
+
+	result.InternalLinks = []entity.Link{}
+	site := ""
+	if baseParsed != nil {
+		site = graphHost(baseParsed.Host)
+	}
+	internal := make(map[string]bool)
+	addInternal := func(link entity.Link) {
+		href := strings.TrimSpace(link.URL)
+		if href == "" || strings.HasPrefix(href, "#") {
+			return
+		}
+		absoluteURL := uc.resolveURL(baseURL, href)
+		if absoluteURL == "" || internal[absoluteURL] || !sameSite(absoluteURL, site) {
+			return
+		}
+		internal[absoluteURL] = true
+		link.URL = absoluteURL
+		link.IsInternal = true
+		link.NoFollow, link.Sponsored, link.UGC = relFlags(link.Rel)
+		result.InternalLinks = append(result.InternalLinks, link)
+	}
+
+	add := func(link entity.Link) {
+		if len(result.Links) >= maxLinks {
+			return
+		}
+		href := strings.TrimSpace(link.URL)
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") || strings.HasPrefix(href, "data:") {
+			return
+		}
+		absoluteURL := uc.resolveURL(baseURL, href)
+		// the same URL may be both an anchor and, say, a rel=next hint
+		key := link.Source + " " + absoluteURL
+		if absoluteURL == "" || linkMap[key] {
+			return
+		}
+		linkMap[key] = true
+
+		link.URL = absoluteURL
+		if parsed, err := url.Parse(absoluteURL); err == nil && baseParsed != nil {
+			link.IsInternal = parsed.Host == baseParsed.Host
+		}
+		link.NoFollow, link.Sponsored, link.UGC = relFlags(link.Rel)
+		result.Links = append(result.Links, link)
+	}
+
+	uc.traverseNode(n, func(node *html.Node) {
+		if node.Type != html.ElementNode {
+			return
+		}
+		var link entity.Link
+		switch node.Data {
+		case "a":
+			link = entity.Link{
+				URL:        htmlAttr(node, "href"),
+				AnchorText: uc.getTextContent(node),
+				Rel:        htmlAttr(node, "rel"),
+				Source:     entity.LinkSourceAnchor,
+				Region:     pageRegion(node),
+			}
+		case "area":
+			link = entity.Link{
+				URL:        htmlAttr(node, "href"),
+				AnchorText: htmlAttr(node, "alt"),
+				Rel:        htmlAttr(node, "rel"),
+				Source:     entity.LinkSourceArea,
+				Region:     pageRegion(node),
+			}
+		default:
+			return
+		}
+		add(link)
+		addInternal(link)
+	})
+
+	uc.traverseNode(n, func(node *html.Node) {
+		if len(result.Links) >= maxLinks || node.Type != html.ElementNode {
+			return
+		}
+		switch node.Data {
+		case "link":
+			if rel := htmlAttr(node, "rel"); isCrawlableLinkRel(rel) {
+				add(entity.Link{
+					URL:        htmlAttr(node, "href"),
+					AnchorText: htmlAttr(node, "title"),
+					Rel:        rel,
+					Source:     entity.LinkSourceLink,
+				})
+			}
+		case "iframe":
+			add(entity.Link{URL: htmlAttr(node, "src"), Source: entity.LinkSourceIframe, Region: pageRegion(node)})
+		case "form":
+			add(entity.Link{URL: htmlAttr(node, "action"), Source: entity.LinkSourceForm, Region: pageRegion(node)})
+		case "img", "source":
+			for _, candidate := range strings.Split(htmlAttr(node, "srcset"), ",") {
+				if fields := strings.Fields(candidate); len(fields) > 0 {
+					add(entity.Link{URL: fields[0], Source: entity.LinkSourceSrcset, Region: pageRegion(node)})
+				}
+			}
+		}
+		if style := htmlAttr(node, "style"); style != "" {
+			for _, m := range cssURLPattern.FindAllStringSubmatch(style, -1) {
+				add(entity.Link{URL: m[1], Source: entity.LinkSourceStyle, Region: pageRegion(node)})
+			}
+		}
+	})
+
+	for _, value := range header.Values("Link") {
+		for _, link := range parseLinkHeader(value) {
+			// preload, preconnect and stylesheet hints are not pages
+			if isCrawlableLinkRel(link.Rel) {
+				link.Source = entity.LinkSourceHeader
+				add(link)
+			}
+		}
+	}
+}
+
+// isCrawlableLinkRel tells the <link> and Link header relations that point
+// to other pages crawlers visit, leaving out stylesheets, icons and preloads.
+func isCrawlableLinkRel(rel string) bool {
+	crawlable := false
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		switch token {
+		case "alternate", "next", "prev", "amphtml":
+			crawlable = true
+		case "stylesheet", "icon":
+			return false
+		}
+	}
+	return crawlable
+}
+
+// relFlags reads the nofollow, sponsored and ugc tokens of a rel value.
+func relFlags(rel string) (nofollow, sponsored, ugc bool) {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		switch token {
+		case "nofollow":
+			nofollow = true
+		case "sponsored":
+			sponsored = true
+		case "ugc":
+			ugc = true
+		}
+	}
+	return nofollow, sponsored, ugc
+}
+
+// parseLinkHeader reads the targets and relations of an HTTP Link header:
+// `<https://example.com/2>; rel="next", <https://example.com/amp>; rel=amphtml`.
+func parseLinkHeader(value string) []entity.Link {
+	var links []entity.Link
+	rest := value
+	for {
+		start := strings.Index(rest, "<")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], ">")
+		if end < 0 {
+			break
+		}
+		link := entity.Link{URL: rest[start+1 : start+end]}
+		rest = rest[start+end+1:]
+
+		params := rest
+		if next := strings.Index(rest, "<"); next >= 0 {
+			params = rest[:next]
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, val, ok := strings.Cut(param, "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "rel") {
+				link.Rel = strings.Trim(strings.TrimSpace(val), `",`)
+			}
+		}
+		links = append(links, link)
+	}
+	return links
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"net/url"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+// maxMixedContent caps the findings stored per result; the count keeps
+// going past it.
+const maxMixedContent = 500
+
+// extractMixedContent lists every sub-resource of an HTTPS page that is
+// referenced over plain HTTP. Pages served over HTTP have no mixed content.
+func (uc *ScrapingUseCase) extractMixedContent(n *html.Node, result *entity.ScrapingResult, pageURL string) {
+	base, err := url.Parse(pageURL)
+	if err != nil || base.Scheme != "https" {
+		return
+	}
+
+	seen := make(map[string]bool)
+	add := func(node *html.Node, attribute, ref, contentType string) {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			return
+		}
+		resolved, err := base.Parse(ref)
+		if err != nil || resolved.Scheme != "http" {
+			return
+		}
+		key := node.Data + " " + attribute + " " + resolved.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		result.MixedContentCount++
+		if len(result.MixedContent) < maxMixedContent {
+			result.MixedContent = append(result.MixedContent, entity.MixedContent{
+				URL:       resolved.String(),
+				Element:   node.Data,
+				Attribute: attribute,
+				Type:      contentType,
+			})
+		}
+	}
+	addSrcset := func(node *html.Node, contentType string) {
+		for _, candidate := range strings.Split(htmlAttr(node, "srcset"), ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 {
+				add(node, "srcset", fields[0], contentType)
+			}
+		}
+	}
+
+	uc.traverseNode(n, func(node *html.Node) {
+		if node.Type != html.ElementNode {
+			return
+		}
+		switch node.Data {
+		case "img":
+			add(node, "src", htmlAttr(node, "src"), entity.MixedContentPassive)
+			addSrcset(node, entity.MixedContentPassive)
+		case "source":
+			// <source> of <picture> uses srcset, of <audio> and <video> src
+			add(node, "src", htmlAttr(node, "src"), entity.MixedContentPassive)
+			addSrcset(node, entity.MixedContentPassive)
+		case "audio", "video":
+			add(node, "src", htmlAttr(node, "src"), entity.MixedContentPassive)
+			add(node, "poster", htmlAttr(node, "poster"), entity.MixedContentPassive)
+		case "input":
+			if strings.EqualFold(htmlAttr(node, "type"), "image") {
+				add(node, "src", htmlAttr(node, "src"), entity.MixedContentPassive)
+			}
+		case "script", "iframe", "frame", "embed", "track":
+			add(node, "src", htmlAttr(node, "src"), entity.MixedContentActive)
+		case "object":
+			add(node, "data", htmlAttr(node, "data"), entity.MixedContentActive)
+		case "form":
+			add(node, "action", htmlAttr(node, "action"), entity.MixedContentActive)
+		case "button":
+			add(node, "formaction", htmlAttr(node, "formaction"), entity.MixedContentActive)
+		case "link":
+			if contentType, ok := linkContentType(node); ok {
+				add(node, "href", htmlAttr(node, "href"), contentType)
+			}
+		}
+	})
+}
+
+// linkContentType classifies the <link> elements that load a resource:
+// icons and preloaded images are passive, stylesheets and other preloads
+// active.
+func linkContentType(node *html.Node) (string, bool) {
+	for _, rel := range strings.Fields(strings.ToLower(htmlAttr(node, "rel"))) {
+		switch rel {
+		case "icon", "apple-touch-icon", "apple-touch-icon-precomposed":
+			return entity.MixedContentPassive, true
+		case "preload", "prefetch":
+			if strings.EqualFold(htmlAttr(node, "as"), "image") {
+				return entity.MixedContentPassive, true
+			}
+			return entity.MixedContentActive, true
+		case "stylesheet", "modulepreload", "manifest":
+			return entity.MixedContentActive, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	maxResources = 300
+	// the page weight probe fetches this many resources at a time, reads at
+	// most maxResourceBytes of each and gives up after resourceProbeTimeout
+	resourceProbeConcurrency = 6
+	maxResourceBytes         = 20 << 20
+	resourceProbeTimeout     = 30 * time.Second
+	largestResources         = 10
+)
+
+// fontFacePattern and cssURLPattern find the url() sources of the
+// @font-face rules of inline stylesheets.
+var (
+	fontFacePattern = regexp.MustCompile(`(?is)@font-face\s*\{[^}]*\}`)
+	cssURLPattern   = regexp.MustCompile(`(?i)url\(\s*["']?([^"')]+)["']?\s*\)`)
+)
+
+// extractResources inventories the scripts, stylesheets, fonts, images,
+// media and iframes of the page, with their preload hints, and tells which
+// of them are third-party and which block rendering.
+func (uc *ScrapingUseCase) extractResources(n *html.Node, result *entity.ScrapingResult, pageURL string) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+	site := registrableDomain(base.Hostname())
+
+	index := make(map[string]int)
+	// fromElement tells the resources seen in the page from those that are
+	// only hinted so far
+	fromElement := make(map[string]bool)
+	add := func(resource entity.Resource, hint bool) {
+		ref := strings.TrimSpace(resource.URL)
+		if ref == "" || strings.HasPrefix(ref, "data:") {
+			return
+		}
+		resolved, err := base.Parse(ref)
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return
+		}
+		resolved.Fragment = ""
+		resource.URL = resolved.String()
+		resource.ThirdParty = registrableDomain(resolved.Hostname()) != site
+
+		i, ok := index[resource.URL]
+		switch {
+		case !ok:
+			if len(result.Resources) >= maxResources {
+				return
+			}
+			index[resource.URL] = len(result.Resources)
+			result.Resources = append(result.Resources, resource)
+		case hint:
+			if result.Resources[i].Hint == "" {
+				result.Resources[i].Hint = resource.Hint
+			}
+		case !fromElement[resource.URL]:
+			// the element takes over from the hint that announced it
+			resource.Hint = result.Resources[i].Hint
+			result.Resources[i] = resource
+		default:
+			return
+		}
+		if !hint {
+			fromElement[resource.URL] = true
+		}
+	}
+
+	var walk func(n *html.Node, inHead bool)
+	walk = func(n *html.Node, inHead bool) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "head":
+				inHead = true
+			case "noscript", "template":
+				return
+			case "script":
+				if src := htmlAttr(n, "src"); src != "" {
+					async, deferred := hasAttr(n, "async"), hasAttr(n, "defer")
+					module := strings.EqualFold(htmlAttr(n, "type"), "module")
+					add(entity.Resource{
+						Type:           entity.ResourceScript,
+						URL:            src,
+						Async:          async,
+						Defer:          deferred || module,
+						RenderBlocking: inHead && !async && !deferred && !module,
+					}, false)
+				}
+			case "link":
+				addLinkResource(n, add)
+			case "style":
+				if n.FirstChild != nil {
+					for _, rule := range fontFacePattern.FindAllString(n.FirstChild.Data, -1) {
+						for _, m := range cssURLPattern.FindAllStringSubmatch(rule, -1) {
+							add(entity.Resource{Type: entity.ResourceFont, URL: m[1]}, false)
+						}
+					}
+				}
+			case "img", "input":
+				if n.Data == "img" || strings.EqualFold(htmlAttr(n, "type"), "image") {
+					add(entity.Resource{Type: entity.ResourceImage, URL: htmlAttr(n, "src")}, false)
+				}
+			case "video", "audio", "source", "track":
+				add(entity.Resource{Type: entity.ResourceMedia, URL: htmlAttr(n, "src")}, false)
+				if n.Data == "video" {
+					add(entity.Resource{Type: entity.ResourceImage, URL: htmlAttr(n, "poster")}, false)
+				}
+			case "iframe", "frame":
+				add(entity.Resource{Type: entity.ResourceIframe, URL: htmlAttr(n, "src")}, false)
+			case "embed":
+				add(entity.Resource{Type: entity.ResourceOther, URL: htmlAttr(n, "src")}, false)
+			case "object":
+				add(entity.Resource{Type: entity.ResourceOther, URL: htmlAttr(n, "data")}, false)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inHead)
+		}
+	}
+	walk(n, false)
+}
+
+// addLinkResource handles the <link> elements that load something:
+// stylesheets, icons and preload or prefetch hints.
+func addLinkResource(n *html.Node, add func(entity.Resource, bool)) {
+	href := htmlAttr(n, "href")
+	for _, rel := range strings.Fields(strings.ToLower(htmlAttr(n, "rel"))) {
+		switch rel {
+		case "stylesheet":
+			if hasAttr(n, "disabled") {
+				return
+			}
+			// print stylesheets load without blocking the first render
+			media := strings.ToLower(strings.TrimSpace(htmlAttr(n, "media")))
+			add(entity.Resource{
+				Type:           entity.ResourceStylesheet,
+				URL:            href,
+				RenderBlocking: media != "print" && media != "speech",
+			}, false)
+			return
+		case "icon", "apple-touch-icon":
+			add(entity.Resource{Type: entity.ResourceImage, URL: href}, false)
+			return
+		case "preload", "prefetch", "modulepreload":
+			resourceType := entity.ResourceOther
+			switch strings.ToLower(htmlAttr(n, "as")) {
+			case "script":
+				resourceType = entity.ResourceScript
+			case "style":
+				resourceType = entity.ResourceStylesheet
+			case "font":
+				resourceType = entity.ResourceFont
+			case "image":
+				resourceType = entity.ResourceImage
+			case "audio", "video", "track":
+				resourceType = entity.ResourceMedia
+			case "document":
+				resourceType = entity.ResourceIframe
+			}
+			if rel == "modulepreload" {
+				resourceType = entity.ResourceScript
+			}
+			add(entity.Resource{Type: resourceType, URL: href, Hint: rel}, true)
+			return
+		}
+	}
+}
+
+// probePageWeight fetches every resource of the inventory to measure its
+// transfer size and compression, and adds them up with the HTML into the
+// page weight. The HTML is counted as decoded, since the page itself is
+// fetched without asking for compression.
+func (uc *ScrapingUseCase) probePageWeight(ctx context.Context, result *entity.ScrapingResult, page *PageResponse) {
+	ctx, cancel := context.WithTimeout(ctx, resourceProbeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, resourceProbeConcurrency)
+	for i := range result.Resources {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(resource *entity.Resource) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			uc.probeResource(ctx, page, resource)
+		}(&result.Resources[i])
+	}
+	wg.Wait()
+
+	weight := &entity.PageWeight{
+		TotalBytes: result.BodyBytes,
+		HTMLBytes:  result.BodyBytes,
+		ByType:     map[string]int64{},
+		Largest:    []entity.Resource{},
+	}
+	for _, resource := range result.Resources {
+		if resource.StatusCode == 0 || resource.StatusCode >= 400 {
+			weight.Failed++
+			continue
+		}
+		weight.Probed++
+		weight.TotalBytes += resource.TransferBytes
+		weight.ByType[resource.Type] += resource.TransferBytes
+		if resource.Encoding == "" && (resource.Type == entity.ResourceScript || resource.Type == entity.ResourceStylesheet) {
+			weight.Uncompressed++
+		}
+		weight.Largest = append(weight.Largest, resource)
+	}
+	sort.SliceStable(weight.Largest, func(a, b int) bool {
+		return weight.Largest[a].TransferBytes > weight.Largest[b].TransferBytes
+	})
+	if len(weight.Largest) > largestResources {
+		weight.Largest = weight.Largest[:largestResources]
+	}
+	result.PageWeight = weight
+}
+
+// probeResource downloads resource as a browser would, asking for
+// compression, and records the bytes on the wire.
+func (uc *ScrapingUseCase) probeResource(ctx context.Context, page *PageResponse, resource *entity.Resource) {
+	req, err := page.NewRequest(ctx, "GET", resource.URL)
+	if err != nil {
+		return
+	}
+	// setting Accept-Encoding ourselves keeps the transport from
+	// decompressing, so the body is counted as transferred
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+
+	resp, err := page.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	n, _ := io.Copy(io.Discard, io.LimitReader(resp.Body, maxResourceBytes))
+	resource.StatusCode = resp.StatusCode
+	resource.TransferBytes = n
+	if encoding := strings.ToLower(resp.Header.Get("Content-Encoding")); encoding != "identity" {
+		resource.Encoding = encoding
+	}
+}
+
+// registrableDomain is the public suffix plus one label of host, e.g.
+// example.co.uk for static.example.co.uk; IPs and unknown hosts are kept
+// as they are.
+func registrableDomain(host string) string {
+	host = strings.ToLower(host)
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
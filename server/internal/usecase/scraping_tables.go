@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTables       = 20
+	maxTableRows    = 1000
+	maxTableColumns = 100
+	// maxCellSpan caps colspan and rowspan so a hostile value cannot blow
+	// up the grid
+	maxCellSpan = 100
+)
+
+// tableCell is a cell of the expanded grid. Spanned cells repeat the text
+// of the cell that spans them.
+type tableCell struct {
+	text   string
+	header bool
+}
+
+// GetResultTable returns the n-th table (0-based) of a result of the user.
+func (uc *ScrapingUseCase) GetResultTable(id, userID int64, n int) (*entity.Table, error) {
+	result, err := uc.GetResult(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n >= len(result.Tables) {
+		return nil, pkgerrors.NotFoundError("table")
+	}
+	return &result.Tables[n], nil
+}
+
+// extractTables turns the data tables of the page into rows of
+// column-named values. Layout tables (role=presentation, nested tables,
+// single rows or columns) are skipped.
+func (uc *ScrapingUseCase) extractTables(doc *html.Node, result *entity.ScrapingResult) {
+	result.Tables = []entity.Table{}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if len(result.Tables) >= maxTables {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "table" {
+			if table, ok := uc.parseTable(n); ok {
+				result.Tables = append(result.Tables, table)
+				return
+			}
+			// layout tables may still wrap data tables
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+}
+
+func (uc *ScrapingUseCase) parseTable(n *html.Node) (entity.Table, bool) {
+	role := strings.ToLower(htmlAttr(n, "role"))
+	if role == "presentation" || role == "none" || containsTable(n) {
+		return entity.Table{}, false
+	}
+
+	var caption string
+	var rows []*html.Node
+	var headerRows int // leading rows that come from <thead>
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "caption":
+			caption = collapseSpaces(uc.getTextContent(c))
+		case "tr":
+			rows = append(rows, c)
+		case "thead", "tbody", "tfoot":
+			for r := c.FirstChild; r != nil; r = r.NextSibling {
+				if r.Type == html.ElementNode && r.Data == "tr" {
+					rows = append(rows, r)
+					if c.Data == "thead" && headerRows == len(rows)-1 {
+						headerRows++
+					}
+				}
+			}
+		}
+	}
+
+	grid, headerRows := uc.expandRows(rows, headerRows)
+	width := 0
+	hasHeaderCells := false
+	for _, row := range grid {
+		width = max(width, len(row))
+		for _, cell := range row {
+			hasHeaderCells = hasHeaderCells || cell.header
+		}
+	}
+	if len(grid) < 2 || width < 2 {
+		return entity.Table{}, false
+	}
+	// without any semantic hint, only regular grids of some size count
+	if headerRows == 0 && !hasHeaderCells && caption == "" && (len(grid) < 3 || !regularGrid(grid)) {
+		return entity.Table{}, false
+	}
+
+	// without <thead>, leading rows made only of <th> are the header
+	if headerRows == 0 {
+		for headerRows < len(grid)-1 && allHeaders(grid[headerRows]) {
+			headerRows++
+		}
+	}
+
+	headerRows = min(headerRows, len(grid))
+	columns := tableColumns(grid[:headerRows], width)
+	table := entity.Table{
+		Caption: caption,
+		Columns: columns,
+		Rows:    make([]map[string]string, 0, len(grid)-headerRows),
+	}
+	for _, row := range grid[headerRows:] {
+		values := make(map[string]string, len(columns))
+		empty := true
+		for i, name := range columns {
+			if i < len(row) {
+				values[name] = row[i].text
+				empty = empty && row[i].text == ""
+			} else {
+				values[name] = ""
+			}
+		}
+		if !empty {
+			table.Rows = append(table.Rows, values)
+		}
+	}
+	if len(table.Rows) == 0 {
+		return entity.Table{}, false
+	}
+	return table, true
+}
+
+// expandRows lays the cells out on a grid, copying cells with colspan and
+// rowspan into every position they cover. It returns how many of the
+// leading headerRows are left once empty rows are dropped.
+func (uc *ScrapingUseCase) expandRows(rows []*html.Node, headerRows int) ([][]tableCell, int) {
+	if len(rows) > maxTableRows {
+		rows = rows[:maxTableRows]
+	}
+	grid := make([][]tableCell, len(rows))
+	filled := make([][]bool, len(rows))
+
+	place := func(r, c int, cell tableCell) {
+		for len(grid[r]) <= c {
+			grid[r] = append(grid[r], tableCell{})
+			filled[r] = append(filled[r], false)
+		}
+		grid[r][c] = cell
+		filled[r][c] = true
+	}
+
+	for r, tr := range rows {
+		col := 0
+		for td := tr.FirstChild; td != nil; td = td.NextSibling {
+			if td.Type != html.ElementNode || (td.Data != "td" && td.Data != "th") {
+				continue
+			}
+			for col < len(filled[r]) && filled[r][col] {
+				col++
+			}
+			if col >= maxTableColumns {
+				break
+			}
+			cell := tableCell{text: collapseSpaces(uc.getTextContent(td)), header: td.Data == "th"}
+			colspan := spanAttr(td, "colspan")
+			rowspan := spanAttr(td, "rowspan")
+			for dr := 0; dr < rowspan && r+dr < len(rows); dr++ {
+				for dc := 0; dc < colspan && col+dc < maxTableColumns; dc++ {
+					place(r+dr, col+dc, cell)
+				}
+			}
+			col += colspan
+		}
+	}
+
+	// drop empty rows and those that only existed as targets of a rowspan
+	// past the end
+	out := grid[:0]
+	kept := 0
+	for r, row := range grid {
+		if len(row) > 0 {
+			out = append(out, row)
+			if r < headerRows {
+				kept++
+			}
+		}
+	}
+	return out, kept
+}
+
+// tableColumns names the columns after the header rows, joining stacked
+// headers with " / ". Missing names become column_N and repeated names get
+// a numeric suffix so every row value has its own key.
+func tableColumns(headers [][]tableCell, width int) []string {
+	columns := make([]string, width)
+	seen := make(map[string]int, width)
+	for i := range columns {
+		var parts []string
+		for _, row := range headers {
+			if i < len(row) && row[i].text != "" {
+				// a colspan header repeats; keep it once
+				if len(parts) == 0 || parts[len(parts)-1] != row[i].text {
+					parts = append(parts, row[i].text)
+				}
+			}
+		}
+		name := strings.Join(parts, " / ")
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+		columns[i] = name
+	}
+	return columns
+}
+
+func spanAttr(n *html.Node, key string) int {
+	span, err := strconv.Atoi(strings.TrimSpace(htmlAttr(n, key)))
+	if err != nil || span < 1 {
+		return 1
+	}
+	return min(span, maxCellSpan)
+}
+
+func allHeaders(row []tableCell) bool {
+	for _, cell := range row {
+		if !cell.header {
+			return false
+		}
+	}
+	return len(row) > 0
+}
+
+// regularGrid reports whether most rows have the same number of cells.
+func regularGrid(grid [][]tableCell) bool {
+	widths := make(map[int]int)
+	for _, row := range grid {
+		widths[len(row)]++
+	}
+	for _, count := range widths {
+		if count*4 >= len(grid)*3 {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTable(table *html.Node) bool {
+	var found bool
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil && !found; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "table" {
+				found = true
+				return
+			}
+			walk(c)
+		}
+	}
+	walk(table)
+	return found
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
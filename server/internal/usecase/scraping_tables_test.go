@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"webscraper-v2/internal/domain/entity"
+
+	"golang.org/x/net/html"
+)
+
+func TestExtractTablesEmptyHeaderRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		found bool
+	}{
+		{
+			name:  "empty row inside thead",
+			html:  `<table><thead><tr><th>a</th><th>b</th></tr><tr></tr><tr><td>1</td><td>2</td></tr></thead></table>`,
+			found: false,
+		},
+		{
+			name:  "empty row inside thead before tbody",
+			html:  `<table><thead><tr><th>a</th><th>b</th></tr><tr></tr></thead><tbody><tr><td>1</td><td>2</td></tr></tbody></table>`,
+			found: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			uc := &ScrapingUseCase{}
+			result := &entity.ScrapingResult{}
+			uc.extractTables(doc, result)
+
+			if got := len(result.Tables) == 1; got != tt.found {
+				t.Fatalf("found table = %v, want %v (%+v)", got, tt.found, result.Tables)
+			}
+			if tt.found {
+				table := result.Tables[0]
+				if len(table.Rows) != 1 || table.Rows[0]["a"] != "1" || table.Rows[0]["b"] != "2" {
+					t.Errorf("rows = %+v, want one row a=1 b=2", table.Rows)
+				}
+			}
+		})
+	}
+}
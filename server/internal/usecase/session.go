@@ -0,0 +1,655 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"webscraper-v2/internal/domain/entity"
+	"webscraper-v2/internal/domain/repository"
+	"webscraper-v2/internal/infrastructure/config"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/validator"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	loginTimeout       = 30 * time.Second
+	maxLoginPageBytes  = 2 << 20
+	defaultUserField   = "username"
+	defaultPassField   = "password"
+	maxSessionNameSize = 100
+)
+
+// loginSession is a logged-in cookie jar cached per session profile.
+type loginSession struct {
+	jar       *cookiejar.Jar
+	loginURL  *url.URL
+	expiresAt time.Time // zero when no cookie carried an expiry
+}
+
+func (s *loginSession) expired() bool {
+	return !s.expiresAt.IsZero() && time.Now().After(s.expiresAt)
+}
+
+// SessionUseCase manages session profiles and runs their login flows. It
+// implements SessionProvider for the scraping use case.
+type SessionUseCase struct {
+	repo       repository.SessionProfileRepository
+	scrapingUC *ScrapingUseCase
+	config     *config.Config
+	validator  *validator.Validator
+
+	mu       sync.Mutex
+	sessions map[int64]*loginSession
+	locks    map[int64]*sync.Mutex
+}
+
+func NewSessionUseCase(repo repository.SessionProfileRepository, scrapingUC *ScrapingUseCase, cfg *config.Config) *SessionUseCase {
+	return &SessionUseCase{
+		repo:       repo,
+		scrapingUC: scrapingUC,
+		config:     cfg,
+		validator:  validator.NewValidator(),
+		sessions:   make(map[int64]*loginSession),
+		locks:      make(map[int64]*sync.Mutex),
+	}
+}
+
+func (uc *SessionUseCase) CreateProfile(req *entity.CreateSessionProfileRequest, userID int64) (*entity.SessionProfile, error) {
+	profile := &entity.SessionProfile{
+		UserID:        userID,
+		Name:          strings.TrimSpace(req.Name),
+		LoginURL:      strings.TrimSpace(req.LoginURL),
+		FormAction:    strings.TrimSpace(req.FormAction),
+		UsernameField: strings.TrimSpace(req.UsernameField),
+		PasswordField: strings.TrimSpace(req.PasswordField),
+		ExtraFields:   req.ExtraFields,
+		Username:      req.Username,
+		Password:      req.Password,
+		SuccessCheck:  req.SuccessCheck,
+	}
+	if err := uc.validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(profile); err != nil {
+		return nil, pkgerrors.DatabaseError("create session profile", err)
+	}
+
+	log.Printf("✅ Session profile created: %s (ID: %d)", profile.Name, profile.ID)
+	return profile.Redacted(), nil
+}
+
+func (uc *SessionUseCase) GetProfiles(userID int64) ([]*entity.SessionProfile, error) {
+	profiles, err := uc.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("get session profiles", err)
+	}
+	for i, profile := range profiles {
+		profiles[i] = profile.Redacted()
+	}
+	return profiles, nil
+}
+
+func (uc *SessionUseCase) GetProfile(id, userID int64) (*entity.SessionProfile, error) {
+	profile, err := uc.findOwnedProfile(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Redacted(), nil
+}
+
+func (uc *SessionUseCase) UpdateProfile(id int64, req *entity.UpdateSessionProfileRequest, userID int64) (*entity.SessionProfile, error) {
+	profile, err := uc.findOwnedProfile(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		profile.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.LoginURL != nil {
+		profile.LoginURL = strings.TrimSpace(*req.LoginURL)
+	}
+	if req.FormAction != nil {
+		profile.FormAction = strings.TrimSpace(*req.FormAction)
+	}
+	if req.UsernameField != nil {
+		profile.UsernameField = strings.TrimSpace(*req.UsernameField)
+	}
+	if req.PasswordField != nil {
+		profile.PasswordField = strings.TrimSpace(*req.PasswordField)
+	}
+	if req.ExtraFields != nil {
+		// the redacted placeholder keeps the stored value of a field
+		extra := make(map[string]string, len(req.ExtraFields))
+		for name, value := range req.ExtraFields {
+			if value == entity.RedactedValue {
+				value = profile.ExtraFields[name]
+			}
+			extra[name] = value
+		}
+		profile.ExtraFields = extra
+	}
+	if req.Username != nil {
+		profile.Username = *req.Username
+	}
+	// the redacted placeholder keeps the stored password
+	if req.Password != nil && *req.Password != entity.RedactedValue {
+		profile.Password = *req.Password
+	}
+	if req.SuccessCheck != nil {
+		profile.SuccessCheck = *req.SuccessCheck
+	}
+	if err := uc.validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(profile); err != nil {
+		return nil, pkgerrors.DatabaseError("update session profile", err)
+	}
+	uc.InvalidateSession(profile.ID)
+
+	log.Printf("✅ Session profile updated: %s (ID: %d)", profile.Name, profile.ID)
+	return profile.Redacted(), nil
+}
+
+func (uc *SessionUseCase) DeleteProfile(id, userID int64) error {
+	profile, err := uc.findOwnedProfile(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(profile.ID); err != nil {
+		return pkgerrors.DatabaseError("delete session profile", err)
+	}
+	uc.InvalidateSession(profile.ID)
+
+	log.Printf("✅ Session profile deleted: %s (ID: %d)", profile.Name, profile.ID)
+	return nil
+}
+
+// TestProfile runs a fresh login with the profile and reports the outcome.
+// A successful login replaces the cached session.
+func (uc *SessionUseCase) TestProfile(ctx context.Context, id, userID int64) (*entity.SessionTestResult, error) {
+	profile, err := uc.findOwnedProfile(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := uc.defaultTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	lock := uc.profileLock(profile.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, result := uc.login(ctx, profile, transport)
+	if session != nil {
+		uc.storeSession(profile.ID, session)
+	}
+	return result, nil
+}
+
+// SessionJar returns a logged-in cookie jar for the profile, logging in when
+// there is no cached session or its cookies have expired.
+func (uc *SessionUseCase) SessionJar(ctx context.Context, profileID, userID int64, transport http.RoundTripper) (http.CookieJar, error) {
+	profile, err := uc.findOwnedProfile(profileID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := uc.profileLock(profileID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	uc.mu.Lock()
+	session := uc.sessions[profileID]
+	uc.mu.Unlock()
+	if session != nil && !session.expired() {
+		return session.jar, nil
+	}
+
+	session, result := uc.login(ctx, profile, transport)
+	if session == nil {
+		log.Printf("❌ Login failed for session profile %s (ID: %d): %s", profile.Name, profile.ID, result.Error)
+		return nil, pkgerrors.InternalError("session login failed", fmt.Errorf("%s", result.Error))
+	}
+	uc.storeSession(profileID, session)
+	log.Printf("🔐 Logged in with session profile %s (ID: %d)", profile.Name, profile.ID)
+	return session.jar, nil
+}
+
+// IsLoggedOut reports whether resp shows that the cached session is no
+// longer valid: a 401 or a redirect back to the login page, followed or,
+// when redirects are not followed, returned as the response.
+func (uc *SessionUseCase) IsLoggedOut(profileID int64, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	uc.mu.Lock()
+	session := uc.sessions[profileID]
+	uc.mu.Unlock()
+	if session == nil || resp.Request == nil {
+		return false
+	}
+	if isLoginPage(resp.Request.URL, session.loginURL) {
+		return true
+	}
+	if location := resp.Header.Get("Location"); isRedirect(resp.StatusCode) && location != "" {
+		if target, err := resp.Request.URL.Parse(location); err == nil {
+			return isLoginPage(target, session.loginURL)
+		}
+	}
+	return false
+}
+
+func isLoginPage(u, loginURL *url.URL) bool {
+	return strings.EqualFold(u.Host, loginURL.Host) &&
+		strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(loginURL.Path, "/")
+}
+
+func (uc *SessionUseCase) InvalidateSession(profileID int64) {
+	uc.mu.Lock()
+	delete(uc.sessions, profileID)
+	uc.mu.Unlock()
+}
+
+func (uc *SessionUseCase) AuthorizeProfile(profileID, userID int64) error {
+	_, err := uc.findOwnedProfile(profileID, userID)
+	return err
+}
+
+func (uc *SessionUseCase) findOwnedProfile(id, userID int64) (*entity.SessionProfile, error) {
+	profile, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("find session profile", err)
+	}
+	if profile == nil {
+		return nil, pkgerrors.NotFoundError("session profile")
+	}
+	if profile.UserID != userID {
+		return nil, pkgerrors.New(pkgerrors.CodeAuthorization, "unauthorized access to session profile", pkgerrors.ErrUnauthorized)
+	}
+	return profile, nil
+}
+
+func (uc *SessionUseCase) validateProfile(profile *entity.SessionProfile) error {
+	if err := uc.validator.ValidateLength(profile.Name, "name", 3, maxSessionNameSize); err != nil {
+		return pkgerrors.ValidationError(err.Error())
+	}
+	if err := uc.validator.ValidateURL(profile.LoginURL); err != nil {
+		return pkgerrors.ValidationError(err.Error())
+	}
+	if profile.FormAction != "" {
+		if _, err := url.Parse(profile.FormAction); err != nil {
+			return pkgerrors.ValidationError("invalid form_action")
+		}
+	}
+	if profile.UsernameField == "" {
+		profile.UsernameField = defaultUserField
+	}
+	if profile.PasswordField == "" {
+		profile.PasswordField = defaultPassField
+	}
+	if err := uc.validator.ValidateRequired(profile.Username, "username"); err != nil {
+		return pkgerrors.ValidationError(err.Error())
+	}
+	if err := uc.validator.ValidateRequired(profile.Password, "password"); err != nil {
+		return pkgerrors.ValidationError(err.Error())
+	}
+	return nil
+}
+
+func (uc *SessionUseCase) profileLock(profileID int64) *sync.Mutex {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	lock, ok := uc.locks[profileID]
+	if !ok {
+		lock = &sync.Mutex{}
+		uc.locks[profileID] = lock
+	}
+	return lock
+}
+
+func (uc *SessionUseCase) storeSession(profileID int64, session *loginSession) {
+	uc.mu.Lock()
+	uc.sessions[profileID] = session
+	uc.mu.Unlock()
+}
+
+// defaultTransport is the transport a scrape with default options would use.
+func (uc *SessionUseCase) defaultTransport() (http.RoundTripper, error) {
+	sel, err := uc.scrapingUC.selectProxy(uc.scrapingUC.DefaultOptions())
+	if err != nil {
+		return nil, pkgerrors.InternalError("failed to select proxy", err)
+	}
+	return uc.scrapingUC.transportFor(sel), nil
+}
+
+// login loads the login page, fills in its form and submits it. The session
+// is nil when the login failed; the result always describes the attempt.
+func (uc *SessionUseCase) login(ctx context.Context, profile *entity.SessionProfile, transport http.RoundTripper) (*loginSession, *entity.SessionTestResult) {
+	result := &entity.SessionTestResult{Cookies: []string{}}
+	fail := func(format string, args ...interface{}) (*loginSession, *entity.SessionTestResult) {
+		result.Error = fmt.Sprintf(format, args...)
+		return nil, result
+	}
+
+	loginURL, err := url.Parse(profile.LoginURL)
+	if err != nil {
+		return fail("invalid login URL: %v", err)
+	}
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return fail("error creating cookie jar: %v", err)
+	}
+	recorder := &cookieExpiryRecorder{next: transport}
+	client := &http.Client{Transport: recorder, Jar: jar, Timeout: loginTimeout}
+	userAgent := uc.scrapingUC.DefaultOptions().UserAgent
+
+	// 1. Login page: form action, method and prefilled (hidden) fields
+	req, err := http.NewRequestWithContext(ctx, "GET", loginURL.String(), nil)
+	if err != nil {
+		return fail("error creating request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fail("error loading login page: %v", err)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxLoginPageBytes))
+	resp.Body.Close()
+	if err != nil {
+		return fail("error reading login page: %v", err)
+	}
+	pageURL := resp.Request.URL
+
+	action := pageURL
+	method := "POST"
+	values := url.Values{}
+	if doc, err := html.Parse(strings.NewReader(string(body))); err == nil {
+		if form := findLoginForm(doc, profile.PasswordField); form != nil {
+			values = formValues(form)
+			if a := strings.TrimSpace(htmlAttr(form, "action")); a != "" {
+				if ref, err := url.Parse(a); err == nil {
+					action = pageURL.ResolveReference(ref)
+				}
+			}
+			if strings.EqualFold(htmlAttr(form, "method"), "get") {
+				method = "GET"
+			}
+		}
+	}
+	if profile.FormAction != "" {
+		ref, err := url.Parse(profile.FormAction)
+		if err != nil {
+			return fail("invalid form action: %v", err)
+		}
+		action = pageURL.ResolveReference(ref)
+	}
+
+	values.Set(profile.UsernameField, profile.Username)
+	values.Set(profile.PasswordField, profile.Password)
+	for name, value := range profile.ExtraFields {
+		values.Set(name, value)
+	}
+
+	// 2. Submit the form
+	submitURL := *action
+	var reqBody io.Reader
+	if method == "GET" {
+		submitURL.RawQuery = values.Encode()
+	} else {
+		reqBody = strings.NewReader(values.Encode())
+	}
+	req, err = http.NewRequestWithContext(ctx, method, submitURL.String(), reqBody)
+	if err != nil {
+		return fail("error creating login request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Referer", pageURL.String())
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		return fail("error submitting login form: %v", err)
+	}
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxLoginPageBytes))
+	resp.Body.Close()
+	if err != nil {
+		return fail("error reading login response: %v", err)
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.FinalURL = resp.Request.URL.String()
+	cookies := jar.Cookies(resp.Request.URL)
+	for _, c := range cookies {
+		result.Cookies = append(result.Cookies, c.Name)
+	}
+	if expiresAt := recorder.earliest(); !expiresAt.IsZero() {
+		result.ExpiresAt = &expiresAt
+	}
+
+	// 3. Success check
+	check := profile.SuccessCheck
+	if resp.StatusCode >= 400 {
+		return fail("login returned status %d", resp.StatusCode)
+	}
+	if check.CookieName != "" && !hasCookie(cookies, check.CookieName) {
+		return fail("cookie %s was not set", check.CookieName)
+	}
+	if check.URLContains != "" && !strings.Contains(result.FinalURL, check.URLContains) {
+		return fail("final URL does not contain %q", check.URLContains)
+	}
+	if check.TextContains != "" && !strings.Contains(string(body), check.TextContains) {
+		return fail("response does not contain %q", check.TextContains)
+	}
+	if check == (entity.SessionSuccessCheck{}) {
+		if doc, err := html.Parse(strings.NewReader(string(body))); err == nil && findLoginForm(doc, profile.PasswordField) != nil {
+			return fail("login form is still present after submitting")
+		}
+	}
+
+	result.Success = true
+	return &loginSession{jar: jar, loginURL: loginURL, expiresAt: recorder.earliest()}, result
+}
+
+// findLoginForm returns the form holding the password field, preferring a
+// field with the configured name over any password input.
+func findLoginForm(doc *html.Node, passwordField string) *html.Node {
+	var byName, byType *html.Node
+	var walk func(n, form *html.Node)
+	walk = func(n, form *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "form":
+				form = n
+			case "input":
+				if form != nil {
+					if byName == nil && htmlAttr(n, "name") == passwordField {
+						byName = form
+					}
+					if byType == nil && strings.EqualFold(htmlAttr(n, "type"), "password") {
+						byType = form
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, form)
+		}
+	}
+	walk(doc, nil)
+	if byName != nil {
+		return byName
+	}
+	return byType
+}
+
+// formValues collects the values a browser would submit for form before the
+// user types anything: hidden and prefilled inputs, checked boxes, selected
+// options and the first named submit button.
+func formValues(form *html.Node) url.Values {
+	values := url.Values{}
+	submitted := false
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			name := htmlAttr(n, "name")
+			switch n.Data {
+			case "input":
+				if name == "" {
+					break
+				}
+				switch strings.ToLower(htmlAttr(n, "type")) {
+				case "checkbox", "radio":
+					if hasAttr(n, "checked") {
+						values.Add(name, orValue(htmlAttr(n, "value"), "on"))
+					}
+				case "submit", "image":
+					if !submitted {
+						values.Add(name, htmlAttr(n, "value"))
+						submitted = true
+					}
+				case "button", "reset", "file":
+				default:
+					values.Add(name, htmlAttr(n, "value"))
+				}
+			case "textarea":
+				if name != "" && n.FirstChild != nil {
+					values.Add(name, n.FirstChild.Data)
+				} else if name != "" {
+					values.Add(name, "")
+				}
+			case "select":
+				if name != "" {
+					if value, ok := selectedOption(n); ok {
+						values.Add(name, value)
+					}
+				}
+				return
+			case "button":
+				if name != "" && !submitted && strings.ToLower(orValue(htmlAttr(n, "type"), "submit")) == "submit" {
+					values.Add(name, htmlAttr(n, "value"))
+					submitted = true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(form)
+	return values
+}
+
+func selectedOption(sel *html.Node) (string, bool) {
+	var first, selected *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "option" {
+			if first == nil {
+				first = n
+			}
+			if selected == nil && hasAttr(n, "selected") {
+				selected = n
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(sel)
+	if selected == nil {
+		selected = first
+	}
+	if selected == nil {
+		return "", false
+	}
+	for _, attr := range selected.Attr {
+		if attr.Key == "value" {
+			return attr.Val, true
+		}
+	}
+	if selected.FirstChild != nil {
+		return strings.TrimSpace(selected.FirstChild.Data), true
+	}
+	return "", true
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func orValue(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func hasCookie(cookies []*http.Cookie, name string) bool {
+	for _, c := range cookies {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cookieExpiryRecorder remembers the earliest expiry among the cookies set
+// during a login, including on intermediate redirects.
+type cookieExpiryRecorder struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	expiresAt time.Time
+}
+
+func (r *cookieExpiryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range resp.Cookies() {
+		var expiry time.Time
+		switch {
+		case c.MaxAge > 0:
+			expiry = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case c.MaxAge == 0 && !c.Expires.IsZero():
+			expiry = c.Expires
+		}
+		// deleted cookies and session cookies do not bound the session
+		if expiry.IsZero() || !expiry.After(now) {
+			continue
+		}
+		if r.expiresAt.IsZero() || expiry.Before(r.expiresAt) {
+			r.expiresAt = expiry
+		}
+	}
+	return resp, nil
+}
+
+func (r *cookieExpiryRecorder) earliest() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.expiresAt
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"webscraper-v2/internal/domain/entity"
+	pkgerrors "webscraper-v2/pkg/errors"
+	"webscraper-v2/pkg/fingerprint"
+
+	"golang.org/x/net/html"
+)
+
+// maxFingerprintHTML is how much of the page the HTML rules are matched
+// against.
+const maxFingerprintHTML = 1 << 20
+
+// detectTechnologies runs the fingerprint rules against the response
+// headers and cookies and the meta tags, scripts and markup of the page.
+func (uc *ScrapingUseCase) detectTechnologies(doc *html.Node, result *entity.ScrapingResult, page *PageResponse) {
+	if uc.fingerprints == nil {
+		return
+	}
+
+	input := fingerprint.Page{
+		Headers: page.Header,
+		Cookies: make(map[string]string),
+		Meta:    make(map[string][]string),
+	}
+	for _, cookie := range (&http.Response{Header: page.Header}).Cookies() {
+		input.Cookies[strings.ToLower(cookie.Name)] = cookie.Value
+	}
+	uc.traverseNode(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode {
+			return
+		}
+		switch node.Data {
+		case "meta":
+			name := strings.ToLower(htmlAttr(node, "name"))
+			if name == "" {
+				name = strings.ToLower(htmlAttr(node, "property"))
+			}
+			if name != "" {
+				input.Meta[name] = append(input.Meta[name], htmlAttr(node, "content"))
+			}
+		case "script":
+			if src := strings.TrimSpace(htmlAttr(node, "src")); src != "" {
+				input.ScriptSrc = append(input.ScriptSrc, uc.resolveURL(page.FinalURL, src))
+			}
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err == nil {
+		markup := buf.String()
+		if len(markup) > maxFingerprintHTML {
+			markup = markup[:maxFingerprintHTML]
+		}
+		input.HTML = markup
+	}
+
+	for _, match := range uc.fingerprints.Analyze(input) {
+		result.Technologies = append(result.Technologies, entity.Technology{
+			Name:       match.Name,
+			Categories: match.Categories,
+			Version:    match.Version,
+			Confidence: match.Confidence,
+			Website:    match.Website,
+		})
+	}
+}
+
+// FindByTechnology lists the results of the user on which the named
+// technology was detected, newest first.
+func (uc *ScrapingUseCase) FindByTechnology(userID int64, name string) ([]*entity.TechnologyUsage, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, pkgerrors.ValidationError("technology name is required")
+	}
+	usages, err := uc.repo.FindByTechnology(userID, name)
+	if err != nil {
+		return nil, pkgerrors.DatabaseError("find results by technology", err)
+	}
+	return usages, nil
+}
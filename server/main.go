@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"log"
 	"os"
 	"os/signal"
@@ -10,8 +12,12 @@ import (
 	"webscraper-v2/internal/infrastructure/config"
 	"webscraper-v2/internal/infrastructure/database"
 	"webscraper-v2/internal/infrastructure/persistence"
+	"webscraper-v2/internal/infrastructure/proxy"
 	"webscraper-v2/internal/presentation/server"
 	"webscraper-v2/internal/usecase"
+	"webscraper-v2/pkg/crypto"
+	"webscraper-v2/pkg/fingerprint"
+	"webscraper-v2/pkg/netguard"
 )
 
 func main() {
@@ -32,26 +38,68 @@ func main() {
 		}
 	}()
 
+	// Cipher for credentials stored at rest
+	credentialCipher, err := crypto.NewCipher(cfg.Auth.EncryptionKey)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize credential encryption: %v", err)
+	}
+
 	// Initialize repositories
 	scrapingRepo := persistence.NewScrapingRepository(db)
 	userRepo := persistence.NewUserRepository(db)
-	scheduleRepo := persistence.NewScheduleRepository(db)
+	scheduleRepo := persistence.NewScheduleRepository(db, credentialCipher)
+	sessionRepo := persistence.NewSessionRepository(db, credentialCipher)
+	failureRepo := persistence.NewScrapeFailureRepository(db)
+	jobRepo := persistence.NewScrapeJobRepository(db, credentialCipher)
+	batchRepo := persistence.NewBatchRepository(db)
 
 	// Initialize token repository
 	tokenRepo := persistence.NewSQLiteTokenRepository(db)
 
 	log.Println("✅ Database and repositories initialized")
 
+	// Initialize proxy pools
+	proxyManager, err := proxy.NewManager(cfg.Scraping.Proxy)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize proxy pools: %v", err)
+	}
+
+	// Guard against fetches to internal addresses (SSRF)
+	guard, err := netguard.New(netguard.Options{
+		BlockedCIDRs: cfg.Security.BlockedCIDRs,
+		AllowedCIDRs: cfg.Security.AllowedCIDRs,
+		AllowedHosts: cfg.Security.AllowedHosts,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize network guard: %v", err)
+	}
+
+	// Technology fingerprint rules; without the file detection is off
+	fingerprints, err := fingerprint.Load(cfg.Scraping.FingerprintsFile)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		log.Printf("⚠️  Fingerprint rules %s not found, technology detection disabled", cfg.Scraping.FingerprintsFile)
+	case err != nil:
+		log.Fatalf("❌ Failed to load fingerprint rules: %v", err)
+	default:
+		log.Printf("✅ Loaded %d technology fingerprints", fingerprints.Len())
+	}
+
 	// Initialize use cases
-	scrapingUC := usecase.NewScrapingUseCase(scrapingRepo, cfg)
+	scrapingUC := usecase.NewScrapingUseCase(scrapingRepo, failureRepo, cfg, proxyManager, guard)
+	scrapingUC.SetFingerprints(fingerprints)
 	authUC := usecase.NewAuthUseCase(userRepo, tokenRepo, cfg)
 	scheduleUC := usecase.NewScheduleUseCase(scheduleRepo, scrapingUC, cfg)
+	sessionUC := usecase.NewSessionUseCase(sessionRepo, scrapingUC, cfg)
+	scrapingUC.SetSessionProvider(sessionUC)
+	jobUC := usecase.NewJobUseCase(jobRepo, scrapingUC, cfg)
+	batchUC := usecase.NewBatchUseCase(batchRepo, scrapingUC, cfg)
 	chatUC := usecase.NewChatUseCase(cfg)
 
 	log.Println("✅ Use cases initialized")
 
 	// Initialize server
-	srv := server.NewServer(cfg.Server.Port, cfg, scrapingUC, authUC, scheduleUC, chatUC)
+	srv := server.NewServer(cfg.Server.Port, cfg, scrapingUC, authUC, scheduleUC, sessionUC, jobUC, batchUC, chatUC)
 
 	// Setup graceful shutdown
 	shutdownChan := make(chan os.Signal, 1)
@@ -88,6 +136,12 @@ func main() {
 		scheduleUC.StopScheduler() // Detener scheduler
 		log.Println("  ✅ Scheduler stopped")
 
+		jobUC.Stop()
+		log.Println("  ✅ Job workers stopped")
+
+		batchUC.Stop()
+		log.Println("  ✅ Batches stopped")
+
 		log.Println("✅ Shutdown complete")
 	}
 }
@@ -11,6 +11,25 @@ var formats = []string{
 	"2006-01-02T15:04:05",
 	"2006-01-02 15:04:05",
 	time.DateTime,
+	// dates as published in web pages: ISO 8601 variants, HTTP dates and
+	// the usual written forms
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	time.DateOnly,
+	"2006/01/02",
+	"20060102",
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.ANSIC,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
 }
 
 func Parse(dateStr string) (time.Time, error) {
@@ -0,0 +1,381 @@
+// Package fingerprint detects the technologies behind a web page (CMS,
+// frameworks, analytics, CDNs...) from rules in the Wappalyzer format:
+// patterns over response headers, cookies, meta tags, script URLs and the
+// HTML, with optional version and confidence tags.
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Page is what a page exposes to the rules. Meta and Cookies are keyed by
+// lower-case name.
+type Page struct {
+	Headers   http.Header
+	Cookies   map[string]string
+	Meta      map[string][]string
+	ScriptSrc []string
+	HTML      string
+}
+
+// Match is a technology detected on a page. Confidence goes from 1 to 100.
+type Match struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	Version    string   `json:"version,omitempty"`
+	Confidence int      `json:"confidence"`
+	Website    string   `json:"website,omitempty"`
+}
+
+// Engine holds the compiled rules. It is safe for concurrent use.
+type Engine struct {
+	technologies []*technology
+	byName       map[string]*technology
+}
+
+type technology struct {
+	name       string
+	categories []string
+	website    string
+	headers    map[string][]*pattern
+	cookies    map[string][]*pattern
+	meta       map[string][]*pattern
+	scriptSrc  []*pattern
+	html       []*pattern
+	implies    []*pattern
+}
+
+// pattern is a rule regex with its tags: `regex\;version:\1\;confidence:50`.
+// For implies the "regex" is the implied technology name.
+type pattern struct {
+	source     string
+	re         *regexp.Regexp
+	version    string
+	confidence int
+}
+
+// rule mirrors one entry of the rule file.
+type rule struct {
+	Cats      []string                 `json:"cats"`
+	Website   string                   `json:"website"`
+	Headers   map[string]string        `json:"headers"`
+	Cookies   map[string]string        `json:"cookies"`
+	Meta      map[string]stringOrSlice `json:"meta"`
+	ScriptSrc stringOrSlice            `json:"scriptSrc"`
+	HTML      stringOrSlice            `json:"html"`
+	Implies   stringOrSlice            `json:"implies"`
+}
+
+// stringOrSlice accepts both "x" and ["x", "y"], as the format allows.
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = []string{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*s = many
+	return nil
+}
+
+// Load reads a rule file: a JSON object mapping technology names to rules.
+func Load(path string) (*Engine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse compiles the rules read from r.
+func Parse(r io.Reader) (*Engine, error) {
+	var rules map[string]rule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid rule file: %w", err)
+	}
+
+	e := &Engine{byName: make(map[string]*technology, len(rules))}
+	for name, rule := range rules {
+		tech, err := compile(name, rule)
+		if err != nil {
+			return nil, fmt.Errorf("technology %s: %w", name, err)
+		}
+		e.technologies = append(e.technologies, tech)
+		e.byName[strings.ToLower(name)] = tech
+	}
+	sort.Slice(e.technologies, func(i, j int) bool {
+		return e.technologies[i].name < e.technologies[j].name
+	})
+
+	for _, tech := range e.technologies {
+		for _, implied := range tech.implies {
+			if _, ok := e.byName[strings.ToLower(implied.source)]; !ok {
+				return nil, fmt.Errorf("technology %s implies unknown technology %s", tech.name, implied.source)
+			}
+		}
+	}
+	return e, nil
+}
+
+// Len is the number of technologies known to the engine.
+func (e *Engine) Len() int {
+	return len(e.technologies)
+}
+
+// Analyze returns the technologies detected on page, with the ones they
+// imply, sorted by name.
+func (e *Engine) Analyze(page Page) []Match {
+	found := make(map[string]*Match)
+	for _, tech := range e.technologies {
+		if match := tech.analyze(page); match != nil {
+			found[tech.name] = match
+		}
+	}
+
+	// implied technologies inherit the confidence of the one implying them
+	queue := make([]string, 0, len(found))
+	for name := range found {
+		queue = append(queue, name)
+	}
+	sort.Strings(queue)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		match := found[name]
+		for _, implied := range e.byName[strings.ToLower(name)].implies {
+			tech := e.byName[strings.ToLower(implied.source)]
+			confidence := min(match.Confidence, implied.confidence)
+			if existing, ok := found[tech.name]; ok {
+				if existing.Confidence >= confidence {
+					continue
+				}
+				existing.Confidence = confidence
+			} else {
+				found[tech.name] = &Match{
+					Name:       tech.name,
+					Categories: tech.categories,
+					Confidence: confidence,
+					Website:    tech.website,
+				}
+			}
+			queue = append(queue, tech.name)
+		}
+	}
+
+	matches := make([]Match, 0, len(found))
+	for _, match := range found {
+		matches = append(matches, *match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i].Name) < strings.ToLower(matches[j].Name)
+	})
+	return matches
+}
+
+func (t *technology) analyze(page Page) *Match {
+	confidence := 0
+	version := ""
+	hit := func(p *pattern, value string) {
+		groups := p.re.FindStringSubmatch(value)
+		if groups == nil {
+			return
+		}
+		confidence += p.confidence
+		// the most specific version wins
+		if v := p.resolveVersion(groups); len(v) > len(version) {
+			version = v
+		}
+	}
+
+	for name, patterns := range t.headers {
+		for _, value := range page.Headers.Values(name) {
+			for _, p := range patterns {
+				hit(p, value)
+			}
+		}
+	}
+	for name, patterns := range t.cookies {
+		for cookie, value := range page.Cookies {
+			if cookieMatches(name, cookie) {
+				for _, p := range patterns {
+					hit(p, value)
+				}
+			}
+		}
+	}
+	for name, patterns := range t.meta {
+		for _, content := range page.Meta[name] {
+			for _, p := range patterns {
+				hit(p, content)
+			}
+		}
+	}
+	for _, src := range page.ScriptSrc {
+		for _, p := range t.scriptSrc {
+			hit(p, src)
+		}
+	}
+	for _, p := range t.html {
+		hit(p, page.HTML)
+	}
+
+	if confidence == 0 {
+		return nil
+	}
+	return &Match{
+		Name:       t.name,
+		Categories: t.categories,
+		Version:    version,
+		Confidence: min(confidence, 100),
+		Website:    t.website,
+	}
+}
+
+// cookieMatches compares a rule cookie name, which may end in a "*"
+// wildcard, with the name of a cookie of the page.
+func cookieMatches(rule, cookie string) bool {
+	if prefix, ok := strings.CutSuffix(rule, "*"); ok {
+		return strings.HasPrefix(cookie, prefix)
+	}
+	return rule == cookie
+}
+
+var (
+	versionGroup   = regexp.MustCompile(`\\(\d)`)
+	versionTernary = regexp.MustCompile(`^\\(\d)\?([^:]*):(.*)$`)
+)
+
+// resolveVersion fills the version template of p with the groups of a
+// match. `\1` is replaced with the first group and `\1?a:b` yields a when
+// the first group matched and b otherwise.
+func (p *pattern) resolveVersion(groups []string) string {
+	if p.version == "" {
+		return ""
+	}
+	group := func(ref string) string {
+		i, _ := strconv.Atoi(ref)
+		if i < len(groups) {
+			return groups[i]
+		}
+		return ""
+	}
+
+	template := p.version
+	if m := versionTernary.FindStringSubmatch(template); m != nil {
+		if group(m[1]) != "" {
+			template = m[2]
+		} else {
+			template = m[3]
+		}
+	}
+	version := versionGroup.ReplaceAllStringFunc(template, func(ref string) string {
+		return group(ref[1:])
+	})
+	return strings.TrimSpace(version)
+}
+
+func compile(name string, r rule) (*technology, error) {
+	tech := &technology{
+		name:       name,
+		categories: r.Cats,
+		website:    r.Website,
+		headers:    make(map[string][]*pattern),
+		cookies:    make(map[string][]*pattern),
+		meta:       make(map[string][]*pattern),
+	}
+	if tech.categories == nil {
+		tech.categories = []string{}
+	}
+
+	for header, source := range r.Headers {
+		p, err := parsePattern(source)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", header, err)
+		}
+		tech.headers[header] = append(tech.headers[header], p)
+	}
+	for cookie, source := range r.Cookies {
+		p, err := parsePattern(source)
+		if err != nil {
+			return nil, fmt.Errorf("cookie %s: %w", cookie, err)
+		}
+		tech.cookies[strings.ToLower(cookie)] = append(tech.cookies[strings.ToLower(cookie)], p)
+	}
+	for meta, sources := range r.Meta {
+		for _, source := range sources {
+			p, err := parsePattern(source)
+			if err != nil {
+				return nil, fmt.Errorf("meta %s: %w", meta, err)
+			}
+			tech.meta[strings.ToLower(meta)] = append(tech.meta[strings.ToLower(meta)], p)
+		}
+	}
+	for _, source := range r.ScriptSrc {
+		p, err := parsePattern(source)
+		if err != nil {
+			return nil, fmt.Errorf("scriptSrc: %w", err)
+		}
+		tech.scriptSrc = append(tech.scriptSrc, p)
+	}
+	for _, source := range r.HTML {
+		p, err := parsePattern(source)
+		if err != nil {
+			return nil, fmt.Errorf("html: %w", err)
+		}
+		tech.html = append(tech.html, p)
+	}
+	for _, source := range r.Implies {
+		p, err := parseTags(source)
+		if err != nil {
+			return nil, fmt.Errorf("implies: %w", err)
+		}
+		tech.implies = append(tech.implies, p)
+	}
+	return tech, nil
+}
+
+// parsePattern compiles a rule pattern, case-insensitively. An empty regex
+// only requires the header, cookie or meta tag to be present.
+func parsePattern(source string) (*pattern, error) {
+	p, err := parseTags(source)
+	if err != nil {
+		return nil, err
+	}
+	if p.re, err = regexp.Compile("(?i)" + p.source); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func parseTags(source string) (*pattern, error) {
+	parts := strings.Split(source, `\;`)
+	p := &pattern{source: parts[0], confidence: 100}
+	for _, tag := range parts[1:] {
+		key, value, _ := strings.Cut(tag, ":")
+		switch key {
+		case "version":
+			p.version = value
+		case "confidence":
+			confidence, err := strconv.Atoi(value)
+			if err != nil || confidence < 0 || confidence > 100 {
+				return nil, fmt.Errorf("invalid confidence %q", value)
+			}
+			p.confidence = confidence
+		}
+	}
+	return p, nil
+}
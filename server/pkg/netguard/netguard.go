@@ -0,0 +1,199 @@
+// Package netguard blocks server-side requests to internal addresses
+// (loopback, private, link-local and other reserved ranges).
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// ErrBlocked is returned (wrapped) when a destination is not allowed.
+var ErrBlocked = errors.New("destination address is not allowed")
+
+// defaultBlocked are the ranges no scrape should reach unless allowlisted.
+var defaultBlocked = []string{
+	"0.0.0.0/8",      // "this" network
+	"10.0.0.0/8",     // RFC1918
+	"100.64.0.0/10",  // carrier-grade NAT
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link-local, cloud metadata endpoints
+	"172.16.0.0/12",  // RFC1918
+	"192.0.0.0/24",   // IETF protocol assignments
+	"192.168.0.0/16", // RFC1918
+	"198.18.0.0/15",  // benchmarking
+	"224.0.0.0/4",    // multicast
+	"240.0.0.0/4",    // reserved and broadcast
+	"::/128",         // unspecified
+	"::1/128",        // loopback
+	"64:ff9b::/96",   // well-known NAT64, maps onto any IPv4 address
+	"64:ff9b:1::/48", // local-use NAT64
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link-local
+	"ff00::/8",       // multicast
+}
+
+// Options configures a Guard. CIDRs and hosts in the allow lists are
+// reachable even when they fall inside a blocked range.
+type Options struct {
+	BlockedCIDRs []string
+	AllowedCIDRs []string
+	// AllowedHosts are exact host names or "*.example.com" wildcards.
+	AllowedHosts []string
+}
+
+type Guard struct {
+	blocked  []*net.IPNet
+	allowed  []*net.IPNet
+	hosts    []string
+	resolver *net.Resolver
+}
+
+func New(opts Options) (*Guard, error) {
+	blocked, err := parseCIDRs(append(append([]string{}, defaultBlocked...), opts.BlockedCIDRs...))
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := parseCIDRs(opts.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(opts.AllowedHosts))
+	for _, host := range opts.AllowedHosts {
+		if host = normalizeHost(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return &Guard{blocked: blocked, allowed: allowed, hosts: hosts, resolver: net.DefaultResolver}, nil
+}
+
+func parseCIDRs(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			// a single address
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// HostAllowed reports whether host is on the host allowlist.
+func (g *Guard) HostAllowed(host string) bool {
+	host = normalizeHost(host)
+	for _, allowed := range g.hosts {
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckIP returns an error wrapping ErrBlocked if ip is in a blocked range
+// and not allowlisted.
+func (g *Guard) CheckIP(ip net.IP) error {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, ipNet := range g.allowed {
+		if ipNet.Contains(ip) {
+			return nil
+		}
+	}
+	for _, ipNet := range g.blocked {
+		if ipNet.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrBlocked, ip)
+		}
+	}
+	return nil
+}
+
+// CheckHost resolves host and checks every address it resolves to.
+func (g *Guard) CheckHost(ctx context.Context, host string) error {
+	if g.HostAllowed(host) {
+		return nil
+	}
+	host = normalizeHost(host)
+	if ip := net.ParseIP(host); ip != nil {
+		return g.CheckIP(ip)
+	}
+	addrs, err := g.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := g.CheckIP(addr.IP); err != nil {
+			return fmt.Errorf("%s resolves to a blocked address: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// DialContext wraps dialer so the address of every connection is checked
+// right before connecting, after DNS resolution. Checking at dial time
+// covers redirects and defeats DNS rebinding between check and use.
+func (g *Guard) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	guarded := *dialer
+	guarded.Control = func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return fmt.Errorf("%w: unresolved address %s", ErrBlocked, address)
+		}
+		return g.CheckIP(ip)
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && g.HostAllowed(host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+}
+
+// RoundTripper checks the host of every request before handing it to next.
+// It is meant for proxied transports, where the dialer only sees the proxy
+// address; the proxy resolves the host again, so this is best effort.
+func (g *Guard) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := g.CheckHost(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimSuffix(host, "]"), "[")
+	return strings.TrimSuffix(host, ".")
+}
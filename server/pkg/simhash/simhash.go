@@ -0,0 +1,74 @@
+// Package simhash computes 64-bit SimHash fingerprints of text, so that
+// near-duplicate documents have fingerprints a few bits apart.
+package simhash
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of consecutive words hashed together.
+const shingleSize = 3
+
+// Fingerprint returns the SimHash of text, built from overlapping word
+// shingles. Case and punctuation are ignored. Text without words returns 0.
+func Fingerprint(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(shingle string) {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	if len(words) < shingleSize {
+		add(strings.Join(words, " "))
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		add(strings.Join(words[i:i+shingleSize], " "))
+	}
+
+	var fingerprint uint64
+	for i, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << i
+		}
+	}
+	return fingerprint
+}
+
+// Distance is the number of bits that differ between a and b.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similarity is 1 for equal fingerprints and 0 for opposite ones.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(Distance(a, b))/64
+}
+
+// Format encodes a fingerprint as 16 hex digits, the form it is stored in.
+func Format(fingerprint uint64) string {
+	return fmt.Sprintf("%016x", fingerprint)
+}
+
+// Parse decodes a fingerprint written by Format.
+func Parse(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
@@ -0,0 +1,88 @@
+// Package trackers classifies third-party hosts against a bundled list of
+// known trackers (analytics, advertising, social and tag managers) and of
+// consent management platforms.
+package trackers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+// Tracker categories.
+const (
+	CategoryAnalytics   = "analytics"
+	CategoryAdvertising = "advertising"
+	CategorySocial      = "social"
+	CategoryTagManager  = "tag_manager"
+)
+
+// Tracker is an entry of the list.
+type Tracker struct {
+	Company  string `json:"company"`
+	Category string `json:"category"`
+}
+
+//go:embed trackers.json
+var bundled []byte
+
+var list struct {
+	Trackers         map[string]Tracker `json:"trackers"`
+	ConsentPlatforms struct {
+		Domains map[string]string `json:"domains"`
+		Cookies map[string]string `json:"cookies"`
+	} `json:"consent_platforms"`
+}
+
+func init() {
+	if err := json.Unmarshal(bundled, &list); err != nil {
+		panic("trackers: invalid bundled list: " + err.Error())
+	}
+}
+
+// Lookup classifies host. Entries match the host and its subdomains, and
+// the most specific one wins, so bat.bing.com can be listed apart from
+// bing.com.
+func Lookup(host string) (Tracker, bool) {
+	for domain := range suffixes(host) {
+		if tracker, ok := list.Trackers[domain]; ok {
+			return tracker, true
+		}
+	}
+	return Tracker{}, false
+}
+
+// ConsentPlatformByHost names the consent management platform served from
+// host, if any.
+func ConsentPlatformByHost(host string) (string, bool) {
+	for domain := range suffixes(host) {
+		if name, ok := list.ConsentPlatforms.Domains[domain]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ConsentPlatformByCookie names the consent management platform that sets
+// the named cookie, if any.
+func ConsentPlatformByCookie(name string) (string, bool) {
+	platform, ok := list.ConsentPlatforms.Cookies[name]
+	return platform, ok
+}
+
+// suffixes yields host and each of its parent domains, longest first.
+func suffixes(host string) func(func(string) bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return func(yield func(string) bool) {
+		for domain := host; domain != ""; {
+			if !yield(domain) {
+				return
+			}
+			_, parent, ok := strings.Cut(domain, ".")
+			if !ok {
+				return
+			}
+			domain = parent
+		}
+	}
+}
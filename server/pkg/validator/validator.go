@@ -64,6 +64,16 @@ func (v *Validator) ValidateMaxLength(value, fieldName string, max int) error {
 	return nil
 }
 
+// --- Numeric Validation ---
+
+func (v *Validator) ValidateRange(value int, fieldName string, min, max int) error {
+
+	if value < min || value > max {
+		return fmt.Errorf("%s must be between %d and %d", fieldName, min, max)
+	}
+	return nil
+}
+
 // --- Email Validation ---
 
 func (v *Validator) ValidateEmail(email string) error {